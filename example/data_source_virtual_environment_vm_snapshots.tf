@@ -0,0 +1,18 @@
+data "proxmox_virtual_environment_vm_snapshots" "example" {
+  depends_on = [proxmox_virtual_environment_vm_snapshot.example]
+
+  node_name = proxmox_virtual_environment_vm.example.node_name
+  vm_id     = proxmox_virtual_environment_vm.example.vm_id
+}
+
+output "data_proxmox_virtual_environment_vm_snapshots_example_current_parent" {
+  value = data.proxmox_virtual_environment_vm_snapshots.example.current_parent
+}
+
+output "data_proxmox_virtual_environment_vm_snapshots_example_names" {
+  value = data.proxmox_virtual_environment_vm_snapshots.example.names
+}
+
+output "data_proxmox_virtual_environment_vm_snapshots_example_parents" {
+  value = data.proxmox_virtual_environment_vm_snapshots.example.parents
+}
@@ -0,0 +1,14 @@
+resource "proxmox_virtual_environment_vm_snapshot" "example" {
+  description = "Managed by Terraform"
+  name        = "example"
+  node_name   = proxmox_virtual_environment_vm.example.node_name
+  vm_id       = proxmox_virtual_environment_vm.example.vm_id
+}
+
+output "resource_proxmox_virtual_environment_vm_snapshot_example_creation_date" {
+  value = proxmox_virtual_environment_vm_snapshot.example.creation_date
+}
+
+output "resource_proxmox_virtual_environment_vm_snapshot_example_parent" {
+  value = proxmox_virtual_environment_vm_snapshot.example.parent
+}
@@ -5,64 +5,152 @@
 package proxmox
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 const (
 	// DefaultRootAccount contains the default username and realm for the root account.
 	DefaultRootAccount = "root@pam"
+
+	// ticketLifetime is the amount of time for which a ticket remains valid after being issued by the server.
+	ticketLifetime = 2 * time.Hour
+
+	// ticketRenewalThreshold is the age at which a ticket is renewed before performing another request.
+	ticketRenewalThreshold = 90 * time.Minute
 )
 
 // Authenticate authenticates against the specified endpoint.
 func (c *VirtualEnvironmentClient) Authenticate(reset bool) error {
-	if c.authenticationData != nil && !reset {
+	if c.APIToken != nil {
+		// API tokens are stateless, which means that there is no ticket to acquire.
 		return nil
 	}
 
-	var reqBody *bytes.Buffer
-
-	if c.OTP != nil {
-		reqBody = bytes.NewBufferString(fmt.Sprintf(
-			"username=%s&password=%s&otp=%s",
-			url.QueryEscape(c.Username),
-			url.QueryEscape(c.Password),
-			url.QueryEscape(*c.OTP),
-		))
-	} else {
-		reqBody = bytes.NewBufferString(fmt.Sprintf(
-			"username=%s&password=%s",
-			url.QueryEscape(c.Username),
-			url.QueryEscape(c.Password),
-		))
-	}
+	c.authenticationMutex.Lock()
+	defer c.authenticationMutex.Unlock()
 
-	req, err := http.NewRequest(hmPOST, fmt.Sprintf("%s/%s/access/ticket", c.Endpoint, basePathJSONAPI), reqBody)
+	if c.authenticationData != nil && !reset {
+		ticketAge := time.Since(c.authenticationTime)
 
-	if err != nil {
-		return errors.New("Failed to create authentication request")
+		if ticketAge < ticketRenewalThreshold {
+			return nil
+		}
+
+		// The ticket is about to expire, which means that we need to renew it before it can be used again.
+		// Renewals are performed by using the current ticket as the password, as this does not require an OTP.
+		if ticketAge < ticketLifetime {
+			log.Printf("[DEBUG] Renewing the authentication ticket for user \"%s\"", c.authenticationData.Username)
+
+			err := c.requestTicket(c.authenticationData.Username, *c.authenticationData.Ticket, nil)
+
+			if err == nil {
+				return nil
+			}
+
+			log.Printf("[DEBUG] WARNING: Failed to renew the authentication ticket - Reason: %s", err.Error())
+		}
 	}
 
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	return c.requestTicket(c.Username, c.Password, c.OTP)
+}
 
-	res, err := c.httpClient.Do(req)
+// AuthenticateRequest adds authentication data to a new request.
+func (c *VirtualEnvironmentClient) AuthenticateRequest(req *http.Request) error {
+	if c.APIToken != nil {
+		req.Header.Add("Authorization", fmt.Sprintf("PVEAPIToken=%s", *c.APIToken))
 
-	if err != nil {
-		return errors.New("Failed to retrieve authentication response")
+		return nil
 	}
 
-	err = c.ValidateResponseCode(res)
+	err := c.Authenticate(false)
 
 	if err != nil {
 		return err
 	}
 
+	c.authenticationMutex.Lock()
+	authenticationData := c.authenticationData
+	c.authenticationMutex.Unlock()
+
+	req.AddCookie(&http.Cookie{
+		Name:  "PVEAuthCookie",
+		Value: *authenticationData.Ticket,
+	})
+
+	if req.Method != "GET" {
+		req.Header.Add("CSRFPreventionToken", *authenticationData.CSRFPreventionToken)
+	}
+
+	return nil
+}
+
+// requestTicket requests a new ticket from the server and stores it in the client.
+func (c *VirtualEnvironmentClient) requestTicket(username, password string, otp *string) error {
+	reqBody := fmt.Sprintf(
+		"username=%s&password=%s",
+		url.QueryEscape(username),
+		url.QueryEscape(password),
+	)
+
+	if otp != nil {
+		reqBody = fmt.Sprintf("%s&otp=%s", reqBody, url.QueryEscape(*otp))
+	}
+
+	var res *http.Response
+	var timeRequested time.Time
+
+	for failovers := 0; ; failovers++ {
+		endpoint := c.currentEndpoint()
+		req, err := http.NewRequest(hmPOST, fmt.Sprintf("%s/%s/access/ticket", endpoint, basePathJSONAPI), strings.NewReader(reqBody))
+
+		if err != nil {
+			return errors.New("Failed to create authentication request")
+		}
+
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		timeRequested = time.Now()
+		res, err = c.httpClient.Do(req)
+
+		if err != nil {
+			err = fmt.Errorf("Failed to retrieve authentication response - Reason: %s", err.Error())
+		} else {
+			err = c.ValidateResponseCode(res)
+
+			if err == nil {
+				break
+			}
+
+			res.Body.Close()
+
+			var apiErr *VirtualEnvironmentAPIError
+
+			if !errors.As(err, &apiErr) || !apiErr.isProxyError() {
+				return err
+			}
+		}
+
+		// Authentication requests are idempotent, which means that they can safely be sent to another endpoint.
+		if failovers >= c.endpointCount()-1 {
+			return err
+		}
+
+		nextEndpoint := c.failover(endpoint)
+
+		log.Printf("[DEBUG] WARNING: Failing over authentication request from endpoint %s to %s - Reason: %s", endpoint, nextEndpoint, err.Error())
+	}
+
+	defer res.Body.Close()
+
 	resBody := VirtualEnvironmentAuthenticationResponseBody{}
-	err = json.NewDecoder(res.Body).Decode(&resBody)
+	err := json.NewDecoder(res.Body).Decode(&resBody)
 
 	if err != nil {
 		return errors.New("Failed to decode authentication response")
@@ -85,26 +173,7 @@ func (c *VirtualEnvironmentClient) Authenticate(reset bool) error {
 	}
 
 	c.authenticationData = resBody.Data
-
-	return nil
-}
-
-// AuthenticateRequest adds authentication data to a new request.
-func (c *VirtualEnvironmentClient) AuthenticateRequest(req *http.Request) error {
-	err := c.Authenticate(false)
-
-	if err != nil {
-		return err
-	}
-
-	req.AddCookie(&http.Cookie{
-		Name:  "PVEAuthCookie",
-		Value: *c.authenticationData.Ticket,
-	})
-
-	if req.Method != "GET" {
-		req.Header.Add("CSRFPreventionToken", *c.authenticationData.CSRFPreventionToken)
-	}
+	c.authenticationTime = timeRequested
 
 	return nil
 }
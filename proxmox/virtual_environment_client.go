@@ -6,38 +6,56 @@ package proxmox
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
 
 // NewVirtualEnvironmentClient creates and initializes a VirtualEnvironmentClient instance.
-func NewVirtualEnvironmentClient(endpoint, username, password, otp string, insecure bool) (*VirtualEnvironmentClient, error) {
-	url, err := url.ParseRequestURI(endpoint)
+func NewVirtualEnvironmentClient(endpoint, username, password, otp, apiToken string, insecure bool) (*VirtualEnvironmentClient, error) {
+	endpoint, err := parseEndpoint(endpoint)
 
 	if err != nil {
-		return nil, errors.New("You must specify a valid endpoint for the Proxmox Virtual Environment API (valid: https://host:port/)")
+		return nil, err
 	}
 
-	if url.Scheme != "https" {
-		return nil, errors.New("You must specify a secure endpoint for the Proxmox Virtual Environment API (valid: https://host:port/)")
-	}
+	var pAPIToken *string
 
-	if password == "" {
-		return nil, errors.New("You must specify a password for the Proxmox Virtual Environment API")
-	}
+	if apiToken != "" {
+		tokenParts := strings.SplitN(apiToken, "=", 2)
+
+		if len(tokenParts) != 2 || tokenParts[1] == "" || !strings.Contains(tokenParts[0], "@") || !strings.Contains(tokenParts[0], "!") {
+			return nil, errors.New("You must specify a valid API token for the Proxmox Virtual Environment API (valid: username@realm!tokenid=secret)")
+		}
 
-	if username == "" {
-		return nil, errors.New("You must specify a username for the Proxmox Virtual Environment API")
+		// The token identifier replaces the username as it is the identity used by the server for permission checks.
+		username = tokenParts[0]
+		pAPIToken = &apiToken
+	} else {
+		if password == "" {
+			return nil, errors.New("You must specify a password or an API token for the Proxmox Virtual Environment API")
+		}
+
+		if username == "" {
+			return nil, errors.New("You must specify a username for the Proxmox Virtual Environment API")
+		}
 	}
 
 	var pOTP *string
@@ -46,28 +64,173 @@ func NewVirtualEnvironmentClient(endpoint, username, password, otp string, insec
 		pOTP = &otp
 	}
 
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecure,
+	}
+
 	httpClient := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: insecure,
-			},
+			TLSClientConfig: tlsConfig,
 		},
 	}
 
 	return &VirtualEnvironmentClient{
-		Endpoint:   strings.TrimRight(url.String(), "/"),
-		Insecure:   insecure,
-		OTP:        pOTP,
-		Password:   password,
+		APIToken: pAPIToken,
+		Endpoint: endpoint,
+		Insecure: insecure,
+		OTP:      pOTP,
+		Password: password,
+		RetryPolicy: &VirtualEnvironmentRetryPolicy{
+			MaxDelay:   defaultRetryMaxDelay,
+			MaxRetries: defaultRetryMaxRetries,
+			MinDelay:   defaultRetryMinDelay,
+		},
 		Username:   username,
+		endpoints:  []string{endpoint},
 		httpClient: httpClient,
+		tlsConfig:  tlsConfig,
 	}, nil
 }
 
+// AddEndpoints adds endpoints, which the client fails over to when the current endpoint is unavailable.
+func (c *VirtualEnvironmentClient) AddEndpoints(endpoints ...string) error {
+	parsedEndpoints := make([]string, len(endpoints))
+
+	for i, e := range endpoints {
+		parsedEndpoint, err := parseEndpoint(e)
+
+		if err != nil {
+			return err
+		}
+
+		parsedEndpoints[i] = parsedEndpoint
+	}
+
+	c.endpointMutex.Lock()
+	defer c.endpointMutex.Unlock()
+
+	for _, e := range parsedEndpoints {
+		exists := false
+
+		for _, v := range c.endpoints {
+			if v == e {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			c.endpoints = append(c.endpoints, e)
+		}
+	}
+
+	return nil
+}
+
+// DiscoverEndpoints adds the addresses of the online cluster nodes as endpoints by using the same scheme and port as the primary endpoint.
+func (c *VirtualEnvironmentClient) DiscoverEndpoints(ctx context.Context) error {
+	nodes, err := c.GetClusterStatusContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	primaryEndpoint, err := url.Parse(c.Endpoint)
+
+	if err != nil {
+		return err
+	}
+
+	endpoints := []string{}
+
+	for _, n := range nodes {
+		if n.Type != "node" || n.IP == nil || *n.IP == "" || n.Online == nil || !bool(*n.Online) {
+			continue
+		}
+
+		host := *n.IP
+
+		if port := primaryEndpoint.Port(); port != "" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			host = fmt.Sprintf("[%s]", host)
+		}
+
+		endpoints = append(endpoints, fmt.Sprintf("%s://%s%s", primaryEndpoint.Scheme, host, primaryEndpoint.Path))
+	}
+
+	log.Printf("[DEBUG] Discovered the endpoints %v for the Proxmox Virtual Environment API", endpoints)
+
+	return c.AddEndpoints(endpoints...)
+}
+
+// SetCACertificates replaces the system's certificate authorities with the PEM encoded certificates used to verify the API endpoint.
+func (c *VirtualEnvironmentClient) SetCACertificates(pemCerts []byte) error {
+	certPool := x509.NewCertPool()
+
+	if !certPool.AppendCertsFromPEM(pemCerts) {
+		return errors.New("You must specify at least one valid PEM encoded CA certificate for the Proxmox Virtual Environment API")
+	}
+
+	c.tlsConfig.RootCAs = certPool
+
+	return nil
+}
+
+// SetTLSFingerprints pins the SHA-256 fingerprints of the certificates accepted for the API endpoint.
+// A certificate matching one of the fingerprints is trusted without verifying its chain, which allows self-signed certificates to be used securely.
+func (c *VirtualEnvironmentClient) SetTLSFingerprints(fingerprints []string) error {
+	if len(fingerprints) == 0 {
+		return nil
+	}
+
+	pinnedFingerprints := make([][]byte, len(fingerprints))
+
+	for i, f := range fingerprints {
+		fingerprint, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(f), ":", ""))
+
+		if err != nil || len(fingerprint) != sha256.Size {
+			return fmt.Errorf("You must specify a valid SHA-256 fingerprint for the Proxmox Virtual Environment API (valid: AA:BB:...:FF) - Got: %s", f)
+		}
+
+		pinnedFingerprints[i] = fingerprint
+	}
+
+	if c.Insecure {
+		return nil
+	}
+
+	// The chain verification must be skipped in order to accept self-signed certificates, which means that the leaf certificate must be verified manually.
+	c.tlsConfig.InsecureSkipVerify = true
+	c.tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("The server did not present a certificate")
+		}
+
+		fingerprint := sha256.Sum256(rawCerts[0])
+
+		for _, f := range pinnedFingerprints {
+			if bytes.Equal(fingerprint[:], f) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("The certificate presented by the server does not match any of the pinned fingerprints - Got: %s", formatTLSFingerprint(fingerprint[:]))
+	}
+
+	return nil
+}
+
 // DoRequest performs a HTTP request against a JSON API endpoint.
 func (c *VirtualEnvironmentClient) DoRequest(method, path string, requestBody interface{}, responseBody interface{}) error {
-	var reqBodyReader io.Reader
+	return c.DoRequestContext(context.Background(), method, path, requestBody, responseBody)
+}
+
+// DoRequestContext performs a HTTP request against a JSON API endpoint, which is aborted when the context is cancelled.
+func (c *VirtualEnvironmentClient) DoRequestContext(ctx context.Context, method, path string, requestBody interface{}, responseBody interface{}) error {
+	var reqBodyReader io.ReadSeeker
 	var reqContentLength *int64
+	var secrets []string
 
 	log.Printf("[DEBUG] Performing HTTP %s request (path: %s)", method, path)
 
@@ -79,13 +242,33 @@ func (c *VirtualEnvironmentClient) DoRequest(method, path string, requestBody in
 		pipedBodyReader, pipedBody := requestBody.(*io.PipeReader)
 
 		if multipart {
-			reqBodyReader = multipartData.Reader
+			replayableReader, cleanup, err := newReplayableBody(multipartData.Reader)
+
+			if err != nil {
+				fErr := fmt.Errorf("Failed to buffer multipart body for HTTP %s request (path: %s) - Reason: %s", method, modifiedPath, err.Error())
+				log.Printf("[DEBUG] WARNING: %s", fErr.Error())
+				return fErr
+			}
+
+			defer cleanup()
+
+			reqBodyReader = replayableReader
 			reqBodyType = fmt.Sprintf("multipart/form-data; boundary=%s", multipartData.Boundary)
 			reqContentLength = multipartData.Size
 
 			log.Printf("[DEBUG] Added multipart request body to HTTP %s request (path: %s)", method, modifiedPath)
 		} else if pipedBody {
-			reqBodyReader = pipedBodyReader
+			replayableReader, cleanup, err := newReplayableBody(pipedBodyReader)
+
+			if err != nil {
+				fErr := fmt.Errorf("Failed to buffer piped body for HTTP %s request (path: %s) - Reason: %s", method, modifiedPath, err.Error())
+				log.Printf("[DEBUG] WARNING: %s", fErr.Error())
+				return fErr
+			}
+
+			defer cleanup()
+
+			reqBodyReader = replayableReader
 
 			log.Printf("[DEBUG] Added piped request body to HTTP %s request (path: %s)", method, modifiedPath)
 		} else {
@@ -100,6 +283,10 @@ func (c *VirtualEnvironmentClient) DoRequest(method, path string, requestBody in
 			encodedValues := v.Encode()
 
 			if encodedValues != "" {
+				var redactedValues url.Values
+
+				redactedValues, secrets = redactRequestValues(requestBody, v)
+
 				if method == hmDELETE || method == hmGET || method == hmHEAD {
 					if !strings.Contains(modifiedPath, "?") {
 						modifiedPath = fmt.Sprintf("%s?%s", modifiedPath, encodedValues)
@@ -107,23 +294,148 @@ func (c *VirtualEnvironmentClient) DoRequest(method, path string, requestBody in
 						modifiedPath = fmt.Sprintf("%s&%s", modifiedPath, encodedValues)
 					}
 				} else {
-					reqBodyReader = bytes.NewBufferString(encodedValues)
+					reqBodyReader = strings.NewReader(encodedValues)
 					reqBodyType = "application/x-www-form-urlencoded"
 				}
 
-				log.Printf("[DEBUG] Added request body to HTTP %s request (path: %s) - Body: %s", method, modifiedPath, encodedValues)
+				log.Printf("[DEBUG] Added request body to HTTP %s request (path: %s) - Body: %s", method, redactSecrets(modifiedPath, secrets), redactedValues.Encode())
+			}
+		}
+	}
+
+	res, err := c.doRequestWithRetries(ctx, method, modifiedPath, reqBodyReader, reqBodyType, reqContentLength, secrets)
+
+	if err != nil {
+		log.Printf("[DEBUG] WARNING: %s", redactSecrets(err.Error(), secrets))
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if responseBody != nil {
+		err = json.NewDecoder(res.Body).Decode(responseBody)
+
+		if err != nil {
+			fErr := fmt.Errorf("Failed to decode HTTP %s response (path: %s) - Reason: %s", method, modifiedPath, err.Error())
+			log.Printf("[DEBUG] WARNING: %s", redactSecrets(fErr.Error(), secrets))
+			return fErr
+		}
+	} else {
+		data, _ := ioutil.ReadAll(res.Body)
+		log.Printf("[DEBUG] WARNING: Unhandled HTTP response body: %s", string(data))
+	}
+
+	return nil
+}
+
+// doRequestWithRetries sends a HTTP request and retries it according to the retry policy, if it fails due to a transient error.
+// The secrets are masked in any log messages written while retrying the request.
+func (c *VirtualEnvironmentClient) doRequestWithRetries(ctx context.Context, method, path string, reqBodyReader io.ReadSeeker, reqBodyType string, reqContentLength *int64, secrets []string) (*http.Response, error) {
+	failovers := 0
+	reauthenticated := false
+
+	for attempt := 0; ; attempt++ {
+		endpoint := c.currentEndpoint()
+		res, err := c.doRequestAttempt(ctx, endpoint, method, path, reqBodyReader, reqBodyType, reqContentLength)
+		failover := false
+		retryable := false
+
+		if err != nil {
+			retryable = isRetryableRequestError(method, err)
+			failover = retryable
+		} else {
+			// Tickets can be invalidated before they expire (e.g. by a server restart), which requires a new ticket to be acquired.
+			if res.StatusCode == http.StatusUnauthorized && c.APIToken == nil && !reauthenticated {
+				res.Body.Close()
+
+				log.Printf("[DEBUG] Received an HTTP 401 response - Re-authenticating and replaying HTTP %s request (path: %s)", method, redactSecrets(path, secrets))
+
+				err = c.Authenticate(true)
+
+				if err != nil {
+					return nil, err
+				}
+
+				reauthenticated = true
+				attempt--
+
+				continue
 			}
+
+			err = c.ValidateResponseCode(res)
+
+			if err == nil {
+				return res, nil
+			}
+
+			res.Body.Close()
+
+			var apiErr *VirtualEnvironmentAPIError
+
+			// Proxy errors do not reveal whether the request has been processed, which is why only idempotent requests are replayed.
+			if errors.As(err, &apiErr) {
+				failover = apiErr.isProxyError() && isIdempotentMethod(method)
+				retryable = apiErr.isRetryable() && (!apiErr.isProxyError() || isIdempotentMethod(method))
+			}
+		}
+
+		// Unavailable endpoints are skipped without a delay, as long as there are other endpoints left to try.
+		if failover && failovers < c.endpointCount()-1 {
+			nextEndpoint := c.failover(endpoint)
+
+			log.Printf("[DEBUG] WARNING: Failing over HTTP %s request (path: %s) from endpoint %s to %s - Reason: %s", method, redactSecrets(path, secrets), endpoint, nextEndpoint, redactSecrets(err.Error(), secrets))
+
+			failovers++
+			attempt--
+
+			continue
+		}
+
+		if !retryable || c.RetryPolicy == nil || attempt >= c.RetryPolicy.MaxRetries {
+			return nil, err
+		}
+
+		delay := c.RetryPolicy.delay(attempt)
+
+		log.Printf("[DEBUG] WARNING: Retrying HTTP %s request (path: %s) in %s due to a transient error (attempt: %d/%d) - Reason: %s", method, redactSecrets(path, secrets), delay, attempt+1, c.RetryPolicy.MaxRetries, redactSecrets(err.Error(), secrets))
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Aborted HTTP %s request (path: %s) while waiting to retry - Reason: %w", method, path, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// doRequestAttempt sends a single HTTP request with a body which is rewound before being sent.
+func (c *VirtualEnvironmentClient) doRequestAttempt(ctx context.Context, endpoint, method, path string, reqBodyReader io.ReadSeeker, reqBodyType string, reqContentLength *int64) (*http.Response, error) {
+	var body io.Reader
+
+	if reqBodyReader != nil {
+		size, err := reqBodyReader.Seek(0, io.SeekEnd)
+
+		if err == nil {
+			_, err = reqBodyReader.Seek(0, io.SeekStart)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to rewind the body of HTTP %s request (path: %s) - Reason: %s", method, path, err.Error())
 		}
+
+		if reqContentLength == nil {
+			reqContentLength = &size
+		}
+
+		// The body must not be closed by the transport as it may be needed again, if the request is replayed.
+		body = ioutil.NopCloser(reqBodyReader)
 	} else {
-		reqBodyReader = new(bytes.Buffer)
+		body = new(bytes.Buffer)
 	}
 
-	req, err := http.NewRequest(method, fmt.Sprintf("%s/%s/%s", c.Endpoint, basePathJSONAPI, modifiedPath), reqBodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s/%s", endpoint, basePathJSONAPI, path), body)
 
 	if err != nil {
-		fErr := fmt.Errorf("Failed to create HTTP %s request (path: %s) - Reason: %s", method, modifiedPath, err.Error())
-		log.Printf("[DEBUG] WARNING: %s", fErr.Error())
-		return fErr
+		return nil, fmt.Errorf("Failed to create HTTP %s request (path: %s) - Reason: %s", method, path, err.Error())
 	}
 
 	req.Header.Add("Accept", "application/json")
@@ -139,63 +451,214 @@ func (c *VirtualEnvironmentClient) DoRequest(method, path string, requestBody in
 	err = c.AuthenticateRequest(req)
 
 	if err != nil {
-		log.Printf("[DEBUG] WARNING: %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	res, err := c.httpClient.Do(req)
 
 	if err != nil {
-		fErr := fmt.Errorf("Failed to perform HTTP %s request (path: %s) - Reason: %s", method, modifiedPath, err.Error())
-		log.Printf("[DEBUG] WARNING: %s", fErr.Error())
-		return fErr
+		return nil, fmt.Errorf("Failed to perform HTTP %s request (path: %s) - Reason: %w", method, path, err)
 	}
 
-	defer res.Body.Close()
+	return res, nil
+}
 
-	err = c.ValidateResponseCode(res)
+// currentEndpoint returns the endpoint, which requests are currently sent to.
+func (c *VirtualEnvironmentClient) currentEndpoint() string {
+	c.endpointMutex.Lock()
+	defer c.endpointMutex.Unlock()
 
-	if err != nil {
-		log.Printf("[DEBUG] WARNING: %s", err.Error())
-		return err
+	return c.endpoints[c.endpointIndex]
+}
+
+// endpointCount returns the number of endpoints, which the client can send requests to.
+func (c *VirtualEnvironmentClient) endpointCount() int {
+	c.endpointMutex.Lock()
+	defer c.endpointMutex.Unlock()
+
+	return len(c.endpoints)
+}
+
+// failover switches to the next endpoint, unless another request has already switched away from the failed endpoint.
+func (c *VirtualEnvironmentClient) failover(failedEndpoint string) string {
+	c.endpointMutex.Lock()
+	defer c.endpointMutex.Unlock()
+
+	if c.endpoints[c.endpointIndex] == failedEndpoint {
+		c.endpointIndex = (c.endpointIndex + 1) % len(c.endpoints)
 	}
 
-	if responseBody != nil {
-		err = json.NewDecoder(res.Body).Decode(responseBody)
+	return c.endpoints[c.endpointIndex]
+}
 
-		if err != nil {
-			fErr := fmt.Errorf("Failed to decode HTTP %s response (path: %s) - Reason: %s", method, modifiedPath, err.Error())
-			log.Printf("[DEBUG] WARNING: %s", fErr.Error())
-			return fErr
+// newReplayableBody converts a request body to a reader, which can be rewound in case the request must be replayed.
+func newReplayableBody(r io.Reader) (io.ReadSeeker, func(), error) {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		offset, err := rs.Seek(0, io.SeekCurrent)
+
+		if err == nil {
+			return &replayableBody{ReadSeeker: rs, offset: offset}, func() {}, nil
 		}
-	} else {
-		data, _ := ioutil.ReadAll(res.Body)
-		log.Printf("[DEBUG] WARNING: Unhandled HTTP response body: %s", string(data))
 	}
 
-	return nil
+	// We need to store the body in a temporary file to avoid using high amounts of memory.
+	tempBodyFile, err := ioutil.TempFile("", "request")
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		tempBodyFile.Close()
+		os.Remove(tempBodyFile.Name())
+	}
+
+	_, err = io.Copy(tempBodyFile, r)
+
+	if err != nil {
+		cleanup()
+
+		return nil, nil, err
+	}
+
+	return tempBodyFile, cleanup, nil
 }
 
-// ValidateResponseCode ensures that a response is valid.
+// ValidateResponseCode ensures that a response is valid and returns a VirtualEnvironmentAPIError, if it is not.
 func (c *VirtualEnvironmentClient) ValidateResponseCode(res *http.Response) error {
 	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		status := strings.TrimPrefix(res.Status, fmt.Sprintf("%d ", res.StatusCode))
+		apiErr := &VirtualEnvironmentAPIError{
+			Message:    strings.TrimPrefix(res.Status, fmt.Sprintf("%d ", res.StatusCode)),
+			StatusCode: res.StatusCode,
+		}
+
+		if res.Request != nil && res.Request.URL != nil {
+			apiErr.Path = strings.TrimPrefix(res.Request.URL.Path, fmt.Sprintf("/%s/", basePathJSONAPI))
+		}
 
 		errRes := &VirtualEnvironmentErrorResponseBody{}
 		err := json.NewDecoder(res.Body).Decode(errRes)
 
 		if err == nil && errRes.Errors != nil {
-			errList := []string{}
+			apiErr.Errors = map[string]string{}
 
 			for k, v := range *errRes.Errors {
-				errList = append(errList, fmt.Sprintf("%s: %s", k, strings.TrimRight(v, "\n\r")))
+				apiErr.Errors[k] = strings.TrimRight(v, "\n\r")
 			}
-
-			status = fmt.Sprintf("%s (%s)", status, strings.Join(errList, " - "))
 		}
 
-		return fmt.Errorf("Received an HTTP %d response - Reason: %s", res.StatusCode, status)
+		return apiErr
 	}
 
 	return nil
 }
+
+// waitForCondition evaluates a condition once per delay interval until it is met, the timeout expires or the context is cancelled.
+// The returned error is errWaitTimeout, if the condition was not met in time.
+func waitForCondition(ctx context.Context, timeout int, delay int, condition func() (bool, error)) error {
+	interval := time.Duration(delay) * time.Second
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(time.Duration(timeout) * time.Second)
+	defer timer.Stop()
+
+	for {
+		done, err := condition()
+
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return errWaitTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseEndpoint validates an endpoint and returns it without a trailing slash.
+func parseEndpoint(endpoint string) (string, error) {
+	u, err := url.ParseRequestURI(endpoint)
+
+	if err != nil {
+		return "", errors.New("You must specify a valid endpoint for the Proxmox Virtual Environment API (valid: https://host:port/)")
+	}
+
+	if u.Scheme != "https" {
+		return "", errors.New("You must specify a secure endpoint for the Proxmox Virtual Environment API (valid: https://host:port/)")
+	}
+
+	return strings.TrimRight(u.String(), "/"), nil
+}
+
+// formatTLSFingerprint formats a certificate fingerprint the same way as the Proxmox Virtual Environment API.
+func formatTLSFingerprint(fingerprint []byte) string {
+	parts := make([]string, len(fingerprint))
+
+	for i, b := range fingerprint {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// Seek sets the offset for the next read relative to the original position of the body.
+func (r *replayableBody) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekStart {
+		offset += r.offset
+	}
+
+	position, err := r.ReadSeeker.Seek(offset, whence)
+
+	return position - r.offset, err
+}
+
+// isIdempotentMethod determines whether a request can be replayed without side effects, if it has already been processed.
+func isIdempotentMethod(method string) bool {
+	return method == hmGET || method == hmHEAD
+}
+
+// isRetryableRequestError determines whether a request, which failed before a response was received, can be retried.
+func isRetryableRequestError(method string, err error) bool {
+	var opErr *net.OpError
+
+	if !errors.As(err, &opErr) {
+		return false
+	}
+
+	// Requests which never reached the server can always be retried, while other connection errors are only
+	// safe to retry for idempotent methods.
+	return opErr.Op == "dial" || isIdempotentMethod(method)
+}
+
+// delay calculates the delay before the next attempt by using exponential backoff with jitter.
+func (p *VirtualEnvironmentRetryPolicy) delay(attempt int) time.Duration {
+	delay := p.MinDelay
+
+	for i := 0; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
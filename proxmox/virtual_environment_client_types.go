@@ -5,8 +5,12 @@
 package proxmox
 
 import (
+	"crypto/tls"
+	"errors"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 )
 
 const (
@@ -16,18 +20,40 @@ const (
 	hmHEAD          = "HEAD"
 	hmPOST          = "POST"
 	hmPUT           = "PUT"
+
+	defaultRetryMaxDelay   = 30 * time.Second
+	defaultRetryMaxRetries = 3
+	defaultRetryMinDelay   = 1 * time.Second
+
+	// statusCodeProxyError is the status code returned by the API proxy when it fails to forward a request to another node.
+	statusCodeProxyError = 596
+)
+
+var (
+	// errWaitTimeout is returned by waitForCondition when the timeout expires before the condition is met.
+	errWaitTimeout = errors.New("timeout")
 )
 
 // VirtualEnvironmentClient implements an API client for the Proxmox Virtual Environment API.
 type VirtualEnvironmentClient struct {
-	Endpoint string
-	Insecure bool
-	OTP      *string
-	Password string
-	Username string
-
-	authenticationData *VirtualEnvironmentAuthenticationResponseData
-	httpClient         *http.Client
+	APIToken    *string
+	Endpoint    string
+	Insecure    bool
+	OTP         *string
+	Password    string
+	RetryPolicy *VirtualEnvironmentRetryPolicy
+	SSH         *VirtualEnvironmentSSHConfiguration
+	Username    string
+
+	authenticationData  *VirtualEnvironmentAuthenticationResponseData
+	authenticationMutex sync.Mutex
+	authenticationTime  time.Time
+	endpointIndex       int
+	endpointMutex       sync.Mutex
+	endpoints           []string
+	httpClient          *http.Client
+	sshHostKeysMutex    sync.Mutex
+	tlsConfig           *tls.Config
 }
 
 // VirtualEnvironmentErrorResponseBody contains the body of an error response.
@@ -42,3 +68,17 @@ type VirtualEnvironmentMultiPartData struct {
 	Reader   io.Reader
 	Size     *int64
 }
+
+// VirtualEnvironmentRetryPolicy contains the policy for retrying requests, which have failed due to transient errors.
+type VirtualEnvironmentRetryPolicy struct {
+	MaxDelay   time.Duration
+	MaxRetries int
+	MinDelay   time.Duration
+}
+
+// replayableBody wraps a seekable request body, which may not start at the beginning of the underlying reader.
+type replayableBody struct {
+	io.ReadSeeker
+
+	offset int64
+}
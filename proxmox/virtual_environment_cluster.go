@@ -5,6 +5,7 @@
 package proxmox
 
 import (
+	"context"
 	"errors"
 )
 
@@ -27,3 +28,24 @@ func (c *VirtualEnvironmentClient) GetClusterNextID(vmID *int) (*int, error) {
 
 	return (*int)(resBody.Data), nil
 }
+
+// GetClusterStatus retrieves the status of the cluster and its nodes.
+func (c *VirtualEnvironmentClient) GetClusterStatus() ([]*VirtualEnvironmentClusterStatusResponseData, error) {
+	return c.GetClusterStatusContext(context.Background())
+}
+
+// GetClusterStatusContext retrieves the status of the cluster and its nodes.
+func (c *VirtualEnvironmentClient) GetClusterStatusContext(ctx context.Context) ([]*VirtualEnvironmentClusterStatusResponseData, error) {
+	resBody := &VirtualEnvironmentClusterStatusResponseBody{}
+	err := c.DoRequestContext(ctx, hmGET, "cluster/status", nil, resBody)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resBody.Data == nil {
+		return nil, errors.New("The server did not include a data object in the response")
+	}
+
+	return resBody.Data, nil
+}
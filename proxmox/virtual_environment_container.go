@@ -5,26 +5,26 @@
 package proxmox
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
 	"strings"
-	"time"
 )
 
-// CloneContainer clones a container.
-func (c *VirtualEnvironmentClient) CloneContainer(nodeName string, vmID int, d *VirtualEnvironmentContainerCloneRequestBody) error {
-	return c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/clone", url.PathEscape(nodeName), vmID), d, nil)
+// CloneContainerAsync clones a container asynchronously.
+func (c *VirtualEnvironmentClient) CloneContainerAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentContainerCloneRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/clone", url.PathEscape(nodeName), vmID), d)
 }
 
-// CreateContainer creates a container.
-func (c *VirtualEnvironmentClient) CreateContainer(nodeName string, d *VirtualEnvironmentContainerCreateRequestBody) error {
-	return c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/lxc", url.PathEscape(nodeName)), d, nil)
+// CreateContainerAsync creates a container asynchronously.
+func (c *VirtualEnvironmentClient) CreateContainerAsync(ctx context.Context, nodeName string, d *VirtualEnvironmentContainerCreateRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/lxc", url.PathEscape(nodeName)), d)
 }
 
-// DeleteContainer deletes a container.
-func (c *VirtualEnvironmentClient) DeleteContainer(nodeName string, vmID int) error {
-	return c.DoRequest(hmDELETE, fmt.Sprintf("nodes/%s/lxc/%d", url.PathEscape(nodeName), vmID), nil, nil)
+// DeleteContainerAsync deletes a container asynchronously.
+func (c *VirtualEnvironmentClient) DeleteContainerAsync(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmDELETE, fmt.Sprintf("nodes/%s/lxc/%d", url.PathEscape(nodeName), vmID), nil)
 }
 
 // GetContainer retrieves a container.
@@ -45,8 +45,13 @@ func (c *VirtualEnvironmentClient) GetContainer(nodeName string, vmID int) (*Vir
 
 // GetContainerStatus retrieves the status for a container.
 func (c *VirtualEnvironmentClient) GetContainerStatus(nodeName string, vmID int) (*VirtualEnvironmentContainerGetStatusResponseData, error) {
+	return c.GetContainerStatusContext(context.Background(), nodeName, vmID)
+}
+
+// GetContainerStatusContext retrieves the status for a container.
+func (c *VirtualEnvironmentClient) GetContainerStatusContext(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentContainerGetStatusResponseData, error) {
 	resBody := &VirtualEnvironmentContainerGetStatusResponseBody{}
-	err := c.DoRequest(hmGET, fmt.Sprintf("nodes/%s/lxc/%d/status/current", url.PathEscape(nodeName), vmID), nil, resBody)
+	err := c.DoRequestContext(ctx, hmGET, fmt.Sprintf("nodes/%s/lxc/%d/status/current", url.PathEscape(nodeName), vmID), nil, resBody)
 
 	if err != nil {
 		return nil, err
@@ -59,24 +64,24 @@ func (c *VirtualEnvironmentClient) GetContainerStatus(nodeName string, vmID int)
 	return resBody.Data, nil
 }
 
-// RebootContainer reboots a container.
-func (c *VirtualEnvironmentClient) RebootContainer(nodeName string, vmID int, d *VirtualEnvironmentContainerRebootRequestBody) error {
-	return c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/status/reboot", url.PathEscape(nodeName), vmID), d, nil)
+// RebootContainerAsync reboots a container asynchronously.
+func (c *VirtualEnvironmentClient) RebootContainerAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentContainerRebootRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/status/reboot", url.PathEscape(nodeName), vmID), d)
 }
 
-// ShutdownContainer shuts down a container.
-func (c *VirtualEnvironmentClient) ShutdownContainer(nodeName string, vmID int, d *VirtualEnvironmentContainerShutdownRequestBody) error {
-	return c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/status/shutdown", url.PathEscape(nodeName), vmID), d, nil)
+// ShutdownContainerAsync shuts down a container asynchronously.
+func (c *VirtualEnvironmentClient) ShutdownContainerAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentContainerShutdownRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/status/shutdown", url.PathEscape(nodeName), vmID), d)
 }
 
-// StartContainer starts a container.
-func (c *VirtualEnvironmentClient) StartContainer(nodeName string, vmID int) error {
-	return c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/status/start", url.PathEscape(nodeName), vmID), nil, nil)
+// StartContainerAsync starts a container asynchronously.
+func (c *VirtualEnvironmentClient) StartContainerAsync(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/status/start", url.PathEscape(nodeName), vmID), nil)
 }
 
-// StopContainer stops a container immediately.
-func (c *VirtualEnvironmentClient) StopContainer(nodeName string, vmID int) error {
-	return c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/status/stop", url.PathEscape(nodeName), vmID), nil, nil)
+// StopContainerAsync stops a container immediately asynchronously.
+func (c *VirtualEnvironmentClient) StopContainerAsync(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/lxc/%d/status/stop", url.PathEscape(nodeName), vmID), nil)
 }
 
 // UpdateContainer updates a container.
@@ -86,62 +91,54 @@ func (c *VirtualEnvironmentClient) UpdateContainer(nodeName string, vmID int, d
 
 // WaitForContainerState waits for a container to reach a specific state.
 func (c *VirtualEnvironmentClient) WaitForContainerState(nodeName string, vmID int, state string, timeout int, delay int) error {
-	state = strings.ToLower(state)
-
-	timeDelay := int64(delay)
-	timeMax := float64(timeout)
-	timeStart := time.Now()
-	timeElapsed := timeStart.Sub(timeStart)
-
-	for timeElapsed.Seconds() < timeMax {
-		if int64(timeElapsed.Seconds())%timeDelay == 0 {
-			data, err := c.GetContainerStatus(nodeName, vmID)
+	return c.WaitForContainerStateContext(context.Background(), nodeName, vmID, state, timeout, delay)
+}
 
-			if err != nil {
-				return err
-			}
+// WaitForContainerStateContext waits for a container to reach a specific state or the context to be cancelled.
+func (c *VirtualEnvironmentClient) WaitForContainerStateContext(ctx context.Context, nodeName string, vmID int, state string, timeout int, delay int) error {
+	state = strings.ToLower(state)
 
-			if data.Status == state {
-				return nil
-			}
+	err := waitForCondition(ctx, timeout, delay, func() (bool, error) {
+		data, err := c.GetContainerStatusContext(ctx, nodeName, vmID)
 
-			time.Sleep(1 * time.Second)
+		if err != nil {
+			return false, err
 		}
 
-		time.Sleep(200 * time.Millisecond)
+		return data.Status == state, nil
+	})
 
-		timeElapsed = time.Now().Sub(timeStart)
+	if err == errWaitTimeout {
+		return fmt.Errorf("Timeout while waiting for container \"%d\" to enter the state \"%s\"", vmID, state)
 	}
 
-	return fmt.Errorf("Timeout while waiting for container \"%d\" to enter the state \"%s\"", vmID, state)
+	return err
 }
 
 // WaitForContainerLock waits for a container lock to be released.
 func (c *VirtualEnvironmentClient) WaitForContainerLock(nodeName string, vmID int, timeout int, delay int, ignoreErrorResponse bool) error {
-	timeDelay := int64(delay)
-	timeMax := float64(timeout)
-	timeStart := time.Now()
-	timeElapsed := timeStart.Sub(timeStart)
-
-	for timeElapsed.Seconds() < timeMax {
-		if int64(timeElapsed.Seconds())%timeDelay == 0 {
-			data, err := c.GetContainerStatus(nodeName, vmID)
-
-			if err != nil {
-				if !ignoreErrorResponse {
-					return err
-				}
-			} else if data.Lock == nil || *data.Lock == "" {
-				return nil
+	return c.WaitForContainerLockContext(context.Background(), nodeName, vmID, timeout, delay, ignoreErrorResponse)
+}
+
+// WaitForContainerLockContext waits for a container lock to be released or the context to be cancelled.
+func (c *VirtualEnvironmentClient) WaitForContainerLockContext(ctx context.Context, nodeName string, vmID int, timeout int, delay int, ignoreErrorResponse bool) error {
+	err := waitForCondition(ctx, timeout, delay, func() (bool, error) {
+		data, err := c.GetContainerStatusContext(ctx, nodeName, vmID)
+
+		if err != nil {
+			if !ignoreErrorResponse || ctx.Err() != nil {
+				return false, err
 			}
 
-			time.Sleep(1 * time.Second)
+			return false, nil
 		}
 
-		time.Sleep(200 * time.Millisecond)
+		return data.Lock == nil || *data.Lock == "", nil
+	})
 
-		timeElapsed = time.Now().Sub(timeStart)
+	if err == errWaitTimeout {
+		return fmt.Errorf("Timeout while waiting for container \"%d\" to become unlocked", vmID)
 	}
 
-	return fmt.Errorf("Timeout while waiting for container \"%d\" to become unlocked", vmID)
+	return err
 }
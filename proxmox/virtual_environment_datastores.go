@@ -29,6 +29,22 @@ func (c *VirtualEnvironmentClient) DeleteDatastoreFile(nodeName, datastoreID, vo
 	return nil
 }
 
+// GetDatastore retrieves the configuration of a datastore.
+func (c *VirtualEnvironmentClient) GetDatastore(datastoreID string) (*VirtualEnvironmentDatastoreGetResponseData, error) {
+	resBody := &VirtualEnvironmentDatastoreGetResponseBody{}
+	err := c.DoRequest(hmGET, fmt.Sprintf("storage/%s", url.PathEscape(datastoreID)), nil, resBody)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resBody.Data == nil {
+		return nil, errors.New("The server did not include a data object in the response")
+	}
+
+	return resBody.Data, nil
+}
+
 // ListDatastoreFiles retrieves a list of the files in a datastore.
 func (c *VirtualEnvironmentClient) ListDatastoreFiles(nodeName, datastoreID string) ([]*VirtualEnvironmentDatastoreFileListResponseData, error) {
 	resBody := &VirtualEnvironmentDatastoreFileListResponseBody{}
@@ -24,6 +24,21 @@ type VirtualEnvironmentDatastoreFileListResponseData struct {
 	VolumeID       string  `json:"volid"`
 }
 
+// VirtualEnvironmentDatastoreGetResponseBody contains the body from a datastore get response.
+type VirtualEnvironmentDatastoreGetResponseBody struct {
+	Data *VirtualEnvironmentDatastoreGetResponseData `json:"data,omitempty"`
+}
+
+// VirtualEnvironmentDatastoreGetResponseData contains the data from a datastore get response.
+type VirtualEnvironmentDatastoreGetResponseData struct {
+	ContentTypes *CustomCommaSeparatedList `json:"content,omitempty"`
+	Digest       *string                   `json:"digest,omitempty"`
+	ID           string                    `json:"storage"`
+	Path         *string                   `json:"path,omitempty"`
+	Shared       *CustomBool               `json:"shared,omitempty"`
+	Type         string                    `json:"type"`
+}
+
 // VirtualEnvironmentDatastoreListRequestBody contains the body for a datastore list request.
 type VirtualEnvironmentDatastoreListRequestBody struct {
 	ContentTypes CustomCommaSeparatedList `json:"content,omitempty" url:"content,omitempty,comma"`
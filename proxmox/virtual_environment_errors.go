@@ -0,0 +1,125 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Error returns a human readable representation of the error.
+func (e *VirtualEnvironmentAPIError) Error() string {
+	reason := e.Message
+
+	if len(e.Errors) > 0 {
+		keys := make([]string, 0, len(e.Errors))
+
+		for k := range e.Errors {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		errList := make([]string, len(keys))
+
+		for i, k := range keys {
+			errList[i] = fmt.Sprintf("%s: %s", k, e.Errors[k])
+		}
+
+		reason = fmt.Sprintf("%s (%s)", reason, strings.Join(errList, " - "))
+	}
+
+	return fmt.Sprintf("Received an HTTP %d response (path: %s) - Reason: %s", e.StatusCode, e.Path, reason)
+}
+
+// IsLocked determines whether the error was caused by a resource being locked.
+func (e *VirtualEnvironmentAPIError) IsLocked() bool {
+	return e.StatusCode == http.StatusInternalServerError && e.containsAny(lockedErrorMessages)
+}
+
+// IsNotFound determines whether the error was caused by a resource not existing.
+func (e *VirtualEnvironmentAPIError) IsNotFound() bool {
+	if e.StatusCode == http.StatusNotFound {
+		return true
+	}
+
+	if e.StatusCode != http.StatusInternalServerError {
+		return false
+	}
+
+	// The API reports missing guests and some other objects as internal server errors, which is why only the exact messages are matched.
+	for _, p := range notFoundErrorPatterns {
+		if p.MatchString(e.Message) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPermissionDenied determines whether the error was caused by insufficient privileges.
+func (e *VirtualEnvironmentAPIError) IsPermissionDenied() bool {
+	return e.StatusCode == http.StatusForbidden
+}
+
+// isRetryable determines whether the error is transient and the request may succeed on retry.
+func (e *VirtualEnvironmentAPIError) isRetryable() bool {
+	if e.isProxyError() {
+		return true
+	}
+
+	return e.IsLocked() || (e.StatusCode == http.StatusInternalServerError && e.containsAny(timeoutErrorMessages))
+}
+
+// isProxyError determines whether the error was caused by the endpoint failing to process or forward the request.
+func (e *VirtualEnvironmentAPIError) isProxyError() bool {
+	switch e.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout, statusCodeProxyError:
+		return true
+	}
+
+	return false
+}
+
+// containsAny determines whether the message or any of the parameter errors contain one of the fragments.
+func (e *VirtualEnvironmentAPIError) containsAny(fragments []string) bool {
+	for _, f := range fragments {
+		if strings.Contains(e.Message, f) {
+			return true
+		}
+
+		for _, v := range e.Errors {
+			if strings.Contains(v, f) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsLocked determines whether an error was returned by the API because a resource is locked.
+func IsLocked(err error) bool {
+	var apiErr *VirtualEnvironmentAPIError
+
+	return errors.As(err, &apiErr) && apiErr.IsLocked()
+}
+
+// IsNotFound determines whether an error was returned by the API because a resource does not exist.
+func IsNotFound(err error) bool {
+	var apiErr *VirtualEnvironmentAPIError
+
+	return errors.As(err, &apiErr) && apiErr.IsNotFound()
+}
+
+// IsPermissionDenied determines whether an error was returned by the API because of insufficient privileges.
+func IsPermissionDenied(err error) bool {
+	var apiErr *VirtualEnvironmentAPIError
+
+	return errors.As(err, &apiErr) && apiErr.IsPermissionDenied()
+}
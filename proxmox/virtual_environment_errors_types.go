@@ -0,0 +1,38 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"regexp"
+)
+
+var (
+	// lockedErrorMessages contains fragments of error messages, which indicate that a resource is locked.
+	lockedErrorMessages = []string{
+		"can't lock file",
+		"is locked",
+	}
+
+	// notFoundErrorPatterns contains the exact error messages, which the API returns when a resource does not exist.
+	notFoundErrorPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^Configuration file '[^']+' does not exist`),
+		regexp.MustCompile(`^(group|pool|role|snapshot|storage|volume) '[^']+' does not exist`),
+		regexp.MustCompile(`^no such (alias|IPSet|cluster node) '[^']+'`),
+		regexp.MustCompile(`^no such (user|VM) \('[^']+'\)`),
+	}
+
+	// timeoutErrorMessages contains fragments of error messages, which indicate that the server timed out.
+	timeoutErrorMessages = []string{
+		"got timeout",
+	}
+)
+
+// VirtualEnvironmentAPIError is returned when the Proxmox Virtual Environment API responds with an error.
+type VirtualEnvironmentAPIError struct {
+	Errors     map[string]string
+	Message    string
+	Path       string
+	StatusCode int
+}
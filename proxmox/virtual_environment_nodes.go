@@ -5,16 +5,22 @@
 package proxmox
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
 	"net/url"
 	"sort"
 	"strings"
-	"time"
 
 	"golang.org/x/crypto/ssh"
 )
 
+const (
+	// nodeTaskLogTailLines is the number of lines from the task log, which are included in the error of a failed task.
+	nodeTaskLogTailLines = 20
+)
+
 // ExecuteNodeCommands executes commands on a given node.
 func (c *VirtualEnvironmentClient) ExecuteNodeCommands(nodeName string, commands []string) error {
 	sshClient, err := c.OpenNodeShell(nodeName)
@@ -49,6 +55,20 @@ func (c *VirtualEnvironmentClient) ExecuteNodeCommands(nodeName string, commands
 
 // GetNodeIP retrieves the IP address of a node.
 func (c *VirtualEnvironmentClient) GetNodeIP(nodeName string) (*string, error) {
+	// The cluster status reports the address, which the node name resolves to on the other nodes. This is usually the
+	// address of the management network, unlike the first address of an arbitrary network device.
+	clusterStatus, err := c.GetClusterStatus()
+
+	if err == nil {
+		for _, s := range clusterStatus {
+			if s.Type == "node" && s.Name == nodeName && s.IP != nil && *s.IP != "" {
+				return s.IP, nil
+			}
+		}
+	} else {
+		log.Printf("[DEBUG] Failed to retrieve the address of node \"%s\" from the cluster status: %s", nodeName, err.Error())
+	}
+
 	networkDevices, err := c.ListNodeNetworkDevices(nodeName)
 
 	if err != nil {
@@ -57,13 +77,23 @@ func (c *VirtualEnvironmentClient) GetNodeIP(nodeName string) (*string, error) {
 
 	nodeAddress := ""
 
+	// Prefer the address of an active network device with a gateway, as it is most likely to be reachable.
 	for _, d := range networkDevices {
-		if d.Address != nil {
+		if d.Address != nil && d.Gateway != nil && (d.Active == nil || bool(*d.Active)) {
 			nodeAddress = *d.Address
 			break
 		}
 	}
 
+	if nodeAddress == "" {
+		for _, d := range networkDevices {
+			if d.Address != nil {
+				nodeAddress = *d.Address
+				break
+			}
+		}
+	}
+
 	if nodeAddress == "" {
 		return nil, fmt.Errorf("Failed to determine the IP address of node \"%s\"", nodeName)
 	}
@@ -89,10 +119,49 @@ func (c *VirtualEnvironmentClient) GetNodeTime(nodeName string) (*VirtualEnviron
 	return resBody.Data, nil
 }
 
+// GetNodeTaskLog retrieves a range of lines from the log of a node task.
+func (c *VirtualEnvironmentClient) GetNodeTaskLog(nodeName string, upid string, start int, limit int) ([]string, error) {
+	lines, _, err := c.getNodeTaskLog(context.Background(), nodeName, upid, start, limit)
+
+	return lines, err
+}
+
+// GetNodeTaskLogContext retrieves a range of lines from the log of a node task.
+func (c *VirtualEnvironmentClient) GetNodeTaskLogContext(ctx context.Context, nodeName string, upid string, start int, limit int) ([]string, error) {
+	lines, _, err := c.getNodeTaskLog(ctx, nodeName, upid, start, limit)
+
+	return lines, err
+}
+
+// getNodeTaskLogTail retrieves the last lines from the log of a node task.
+func (c *VirtualEnvironmentClient) getNodeTaskLogTail(ctx context.Context, nodeName string, upid string, limit int) ([]string, error) {
+	// The total number of lines is unknown until the first request has been made, which is why the first line is retrieved separately.
+	lines, total, err := c.getNodeTaskLog(ctx, nodeName, upid, 0, 1)
+
+	if err != nil || total <= 1 {
+		return lines, err
+	}
+
+	start := total - limit
+
+	if start < 0 {
+		start = 0
+	}
+
+	lines, _, err = c.getNodeTaskLog(ctx, nodeName, upid, start, limit)
+
+	return lines, err
+}
+
 // GetNodeTaskStatus retrieves the status of a node task.
 func (c *VirtualEnvironmentClient) GetNodeTaskStatus(nodeName string, upid string) (*VirtualEnvironmentNodeGetTaskStatusResponseData, error) {
+	return c.GetNodeTaskStatusContext(context.Background(), nodeName, upid)
+}
+
+// GetNodeTaskStatusContext retrieves the status of a node task.
+func (c *VirtualEnvironmentClient) GetNodeTaskStatusContext(ctx context.Context, nodeName string, upid string) (*VirtualEnvironmentNodeGetTaskStatusResponseData, error) {
 	resBody := &VirtualEnvironmentNodeGetTaskStatusResponseBody{}
-	err := c.DoRequest(hmGET, fmt.Sprintf("nodes/%s/tasks/%s/status", url.PathEscape(nodeName), url.PathEscape(upid)), nil, resBody)
+	err := c.DoRequestContext(ctx, hmGET, fmt.Sprintf("nodes/%s/tasks/%s/status", url.PathEscape(nodeName), url.PathEscape(upid)), nil, resBody)
 
 	if err != nil {
 		return nil, err
@@ -105,6 +174,43 @@ func (c *VirtualEnvironmentClient) GetNodeTaskStatus(nodeName string, upid strin
 	return resBody.Data, nil
 }
 
+// getNodeTaskLog retrieves a range of lines from the log of a node task as well as the total number of lines.
+func (c *VirtualEnvironmentClient) getNodeTaskLog(ctx context.Context, nodeName string, upid string, start int, limit int) ([]string, int, error) {
+	reqBody := &VirtualEnvironmentNodeGetTaskLogRequestBody{
+		Limit: &limit,
+		Start: &start,
+	}
+
+	resBody := &VirtualEnvironmentNodeGetTaskLogResponseBody{}
+	err := c.DoRequestContext(ctx, hmGET, fmt.Sprintf("nodes/%s/tasks/%s/log", url.PathEscape(nodeName), url.PathEscape(upid)), reqBody, resBody)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resBody.Data == nil {
+		return nil, 0, errors.New("The server did not include a data object in the response")
+	}
+
+	sort.Slice(resBody.Data, func(i, j int) bool {
+		return resBody.Data[i].LineNumber < resBody.Data[j].LineNumber
+	})
+
+	lines := make([]string, len(resBody.Data))
+
+	for i, v := range resBody.Data {
+		lines[i] = v.Text
+	}
+
+	total := len(lines)
+
+	if resBody.Total != nil {
+		total = *resBody.Total
+	}
+
+	return lines, total, nil
+}
+
 // ListNodeNetworkDevices retrieves a list of network devices for a specific nodes.
 func (c *VirtualEnvironmentClient) ListNodeNetworkDevices(nodeName string) ([]*VirtualEnvironmentNodeNetworkDeviceListResponseData, error) {
 	resBody := &VirtualEnvironmentNodeNetworkDeviceListResponseBody{}
@@ -147,21 +253,13 @@ func (c *VirtualEnvironmentClient) ListNodes() ([]*VirtualEnvironmentNodeListRes
 
 // OpenNodeShell establishes a new SSH connection to a node.
 func (c *VirtualEnvironmentClient) OpenNodeShell(nodeName string) (*ssh.Client, error) {
-	nodeAddress, err := c.GetNodeIP(nodeName)
+	sshAddress, err := c.getNodeSSHAddress(nodeName)
 
 	if err != nil {
 		return nil, err
 	}
 
-	ur := strings.Split(c.Username, "@")
-
-	sshConfig := &ssh.ClientConfig{
-		User:            ur[0],
-		Auth:            []ssh.AuthMethod{ssh.Password(c.Password)},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	}
-
-	sshClient, err := ssh.Dial("tcp", *nodeAddress+":22", sshConfig)
+	sshClient, err := c.dialNodeSSH(nodeName, sshAddress)
 
 	if err != nil {
 		return nil, err
@@ -170,6 +268,16 @@ func (c *VirtualEnvironmentClient) OpenNodeShell(nodeName string) (*ssh.Client,
 	return sshClient, nil
 }
 
+// StopNodeTask stops a node task.
+func (c *VirtualEnvironmentClient) StopNodeTask(nodeName string, upid string) error {
+	return c.StopNodeTaskContext(context.Background(), nodeName, upid)
+}
+
+// StopNodeTaskContext stops a node task.
+func (c *VirtualEnvironmentClient) StopNodeTaskContext(ctx context.Context, nodeName string, upid string) error {
+	return c.DoRequestContext(ctx, hmDELETE, fmt.Sprintf("nodes/%s/tasks/%s", url.PathEscape(nodeName), url.PathEscape(upid)), nil, nil)
+}
+
 // UpdateNodeTime updates the time on a node.
 func (c *VirtualEnvironmentClient) UpdateNodeTime(nodeName string, d *VirtualEnvironmentNodeUpdateTimeRequestBody) error {
 	return c.DoRequest(hmPUT, fmt.Sprintf("nodes/%s/time", url.PathEscape(nodeName)), d, nil)
@@ -177,33 +285,49 @@ func (c *VirtualEnvironmentClient) UpdateNodeTime(nodeName string, d *VirtualEnv
 
 // WaitForNodeTask waits for a specific node task to complete.
 func (c *VirtualEnvironmentClient) WaitForNodeTask(nodeName string, upid string, timeout int, delay int) error {
-	timeDelay := int64(delay)
-	timeMax := float64(timeout)
-	timeStart := time.Now()
-	timeElapsed := timeStart.Sub(timeStart)
+	return c.WaitForNodeTaskContext(context.Background(), nodeName, upid, timeout, delay)
+}
 
-	for timeElapsed.Seconds() < timeMax {
-		if int64(timeElapsed.Seconds())%timeDelay == 0 {
-			status, err := c.GetNodeTaskStatus(nodeName, upid)
+// WaitForNodeTaskContext waits for a specific node task to complete or the context to be cancelled.
+func (c *VirtualEnvironmentClient) WaitForNodeTaskContext(ctx context.Context, nodeName string, upid string, timeout int, delay int) error {
+	err := waitForCondition(ctx, timeout, delay, func() (bool, error) {
+		status, err := c.GetNodeTaskStatusContext(ctx, nodeName, upid)
 
-			if err != nil {
-				return err
-			}
+		if err != nil {
+			return false, err
+		}
 
-			if status.Status != "running" {
-				if status.ExitCode != "OK" {
-					return fmt.Errorf("Task \"%s\" on node \"%s\" failed to complete with error: %s", upid, nodeName, status.ExitCode)
-				}
-				return nil
+		if status.Status != "running" {
+			if status.ExitCode != "OK" {
+				return false, fmt.Errorf("Task \"%s\" on node \"%s\" failed to complete with error: %s%s", upid, nodeName, status.ExitCode, c.formatNodeTaskLogTail(ctx, nodeName, upid))
 			}
 
-			time.Sleep(1 * time.Second)
+			return true, nil
 		}
 
-		time.Sleep(200 * time.Millisecond)
+		return false, nil
+	})
+
+	if err == errWaitTimeout {
+		return fmt.Errorf("Timeout while waiting for task \"%s\" on node \"%s\" to complete%s", upid, nodeName, c.formatNodeTaskLogTail(ctx, nodeName, upid))
+	}
+
+	return err
+}
+
+// formatNodeTaskLogTail retrieves the last lines from the log of a node task and formats them for inclusion in an error message.
+func (c *VirtualEnvironmentClient) formatNodeTaskLogTail(ctx context.Context, nodeName string, upid string) string {
+	lines, err := c.getNodeTaskLogTail(ctx, nodeName, upid, nodeTaskLogTailLines)
+
+	if err != nil {
+		log.Printf("[DEBUG] WARNING: Failed to retrieve the log for task \"%s\" on node \"%s\" - Reason: %s", upid, nodeName, err.Error())
+
+		return ""
+	}
 
-		timeElapsed = time.Now().Sub(timeStart)
+	if len(lines) == 0 {
+		return ""
 	}
 
-	return fmt.Errorf("Timeout while waiting for task \"%s\" on node \"%s\" to complete", upid, nodeName)
+	return fmt.Sprintf("\n\nTask log (last %d lines):\n%s", len(lines), strings.Join(lines, "\n"))
 }
@@ -29,6 +29,24 @@ type VirtualEnvironmentNodeGetTimeResponseData struct {
 	UTCTime   CustomTimestamp `json:"time"`
 }
 
+// VirtualEnvironmentNodeGetTaskLogRequestBody contains the data for a node get task log request.
+type VirtualEnvironmentNodeGetTaskLogRequestBody struct {
+	Limit *int `json:"limit,omitempty" url:"limit,omitempty"`
+	Start *int `json:"start,omitempty" url:"start,omitempty"`
+}
+
+// VirtualEnvironmentNodeGetTaskLogResponseBody contains the body from a node get task log response.
+type VirtualEnvironmentNodeGetTaskLogResponseBody struct {
+	Data  []*VirtualEnvironmentNodeGetTaskLogResponseData `json:"data,omitempty"`
+	Total *int                                            `json:"total,omitempty"`
+}
+
+// VirtualEnvironmentNodeGetTaskLogResponseData contains the data from a node get task log response.
+type VirtualEnvironmentNodeGetTaskLogResponseData struct {
+	LineNumber int    `json:"n"`
+	Text       string `json:"t"`
+}
+
 // VirtualEnvironmentNodeGetTaskStatusResponseBody contains the body from a node get task status response.
 type VirtualEnvironmentNodeGetTaskStatusResponseBody struct {
 	Data *VirtualEnvironmentNodeGetTaskStatusResponseData `json:"data,omitempty"`
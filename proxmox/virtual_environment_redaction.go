@@ -0,0 +1,65 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// redactRequestValues returns a copy of the encoded values of a request body with the sensitive fields masked as well as the masked values.
+func redactRequestValues(requestBody interface{}, values url.Values) (url.Values, []string) {
+	bodyType := reflect.TypeOf(requestBody)
+
+	for bodyType != nil && bodyType.Kind() == reflect.Ptr {
+		bodyType = bodyType.Elem()
+	}
+
+	fields, ok := sensitiveRequestBodyFields[bodyType]
+
+	if !ok {
+		return values, nil
+	}
+
+	redactedValues := url.Values{}
+	secrets := []string{}
+
+	for k, v := range values {
+		redactedValues[k] = v
+	}
+
+	for _, f := range fields {
+		fieldValues, ok := values[f]
+
+		if !ok {
+			continue
+		}
+
+		redactedFieldValues := make([]string, len(fieldValues))
+
+		for i, v := range fieldValues {
+			if v != "" {
+				secrets = append(secrets, v)
+			}
+
+			redactedFieldValues[i] = redactedValue
+		}
+
+		redactedValues[f] = redactedFieldValues
+	}
+
+	return redactedValues, secrets
+}
+
+// redactSecrets masks any occurrence of the secrets in a string, which is about to be logged.
+func redactSecrets(s string, secrets []string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+		s = strings.ReplaceAll(s, url.QueryEscape(secret), redactedValue)
+	}
+
+	return s
+}
@@ -0,0 +1,27 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"reflect"
+)
+
+const (
+	redactedValue = "REDACTED"
+)
+
+var (
+	// sensitiveRequestBodyFields contains the names of the fields, which must not be logged, for each type of request body.
+	sensitiveRequestBodyFields = map[reflect.Type][]string{
+		reflect.TypeOf(VirtualEnvironmentCertificateUpdateRequestBody{}):  {"key"},
+		reflect.TypeOf(VirtualEnvironmentContainerCreateRequestBody{}):    {"password"},
+		reflect.TypeOf(VirtualEnvironmentContainerUpdateRequestBody{}):    {"password"},
+		reflect.TypeOf(VirtualEnvironmentUserChangePasswordRequestBody{}): {"password"},
+		reflect.TypeOf(VirtualEnvironmentUserCreateRequestBody{}):         {"keys", "password"},
+		reflect.TypeOf(VirtualEnvironmentUserUpdateRequestBody{}):         {"keys"},
+		reflect.TypeOf(VirtualEnvironmentVMCreateRequestBody{}):           {"cipassword"},
+		reflect.TypeOf(VirtualEnvironmentVMUpdateRequestBody{}):           {"cipassword"},
+	}
+)
@@ -0,0 +1,391 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// dialNodeSSH establishes a new SSH connection to a node, which is tunneled through the jump host, if configured.
+func (c *VirtualEnvironmentClient) dialNodeSSH(nodeName string, address string) (*ssh.Client, error) {
+	sshConfig := c.SSH
+
+	if sshConfig == nil {
+		sshConfig = &VirtualEnvironmentSSHConfiguration{}
+	}
+
+	var agentClient agent.Agent
+
+	if sshConfig.Agent {
+		agentSocket := sshConfig.AgentSocket
+
+		if agentSocket == "" {
+			agentSocket = os.Getenv("SSH_AUTH_SOCK")
+		}
+
+		if agentSocket == "" {
+			return nil, errors.New("Cannot use the SSH agent without a socket (SSH_AUTH_SOCK is not set)")
+		}
+
+		agentConn, err := net.Dial("unix", agentSocket)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to connect to the SSH agent - Reason: %s", err.Error())
+		}
+
+		// The agent is only required during the handshakes.
+		defer agentConn.Close()
+
+		agentClient = agent.NewClient(agentConn)
+	}
+
+	username := sshConfig.Username
+
+	if username == "" {
+		username = strings.Split(c.Username, "@")[0]
+	}
+
+	password := sshConfig.Password
+
+	if password == "" && sshConfig.Username == "" {
+		password = c.Password
+	}
+
+	nodeHost := fmt.Sprintf("node \"%s\"", nodeName)
+	nodeClientConfig, err := c.getSSHClientConfig(
+		nodeHost,
+		address,
+		username,
+		password,
+		sshConfig.PrivateKey,
+		sshConfig.PrivateKeyPassphrase,
+		sshConfig.HostKeyFingerprints[nodeName],
+		agentClient,
+		sshConfig,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if sshConfig.JumpHost == nil {
+		return ssh.Dial("tcp", address, nodeClientConfig)
+	}
+
+	jumpHost := sshConfig.JumpHost
+	jumpAddress := jumpHost.Address
+
+	if _, _, err = net.SplitHostPort(jumpAddress); err != nil {
+		jumpAddress = net.JoinHostPort(jumpAddress, strconv.Itoa(defaultSSHPort))
+	}
+
+	jumpUsername := jumpHost.Username
+
+	if jumpUsername == "" {
+		jumpUsername = username
+	}
+
+	jumpPrivateKey := jumpHost.PrivateKey
+	jumpPrivateKeyPassphrase := jumpHost.PrivateKeyPassphrase
+
+	if jumpPrivateKey == "" {
+		jumpPrivateKey = sshConfig.PrivateKey
+		jumpPrivateKeyPassphrase = sshConfig.PrivateKeyPassphrase
+	}
+
+	jumpHostName := "the jump host"
+	jumpClientConfig, err := c.getSSHClientConfig(
+		jumpHostName,
+		jumpAddress,
+		jumpUsername,
+		jumpHost.Password,
+		jumpPrivateKey,
+		jumpPrivateKeyPassphrase,
+		jumpHost.HostKeyFingerprint,
+		agentClient,
+		sshConfig,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	jumpClient, err := ssh.Dial("tcp", jumpAddress, jumpClientConfig)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to %s (%s) - Reason: %s", jumpHostName, jumpAddress, err.Error())
+	}
+
+	conn, err := jumpClient.Dial("tcp", address)
+
+	if err != nil {
+		jumpClient.Close()
+
+		return nil, fmt.Errorf("Failed to connect to %s (%s) through %s (%s) - Reason: %s", nodeHost, address, jumpHostName, jumpAddress, err.Error())
+	}
+
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, address, nodeClientConfig)
+
+	if err != nil {
+		conn.Close()
+		jumpClient.Close()
+
+		return nil, err
+	}
+
+	sshClient := ssh.NewClient(clientConn, chans, reqs)
+
+	// Close the connection to the jump host once the tunneled connection has been closed.
+	go func() {
+		_ = sshClient.Wait()
+		_ = jumpClient.Close()
+	}()
+
+	return sshClient, nil
+}
+
+// getSSHClientConfig returns the client configuration for an SSH connection to a node or the jump host.
+func (c *VirtualEnvironmentClient) getSSHClientConfig(
+	host string,
+	address string,
+	username string,
+	password string,
+	privateKey string,
+	privateKeyPassphrase string,
+	fingerprint string,
+	agentClient agent.Agent,
+	sshConfig *VirtualEnvironmentSSHConfiguration,
+) (*ssh.ClientConfig, error) {
+	if username == "" {
+		return nil, fmt.Errorf("Cannot connect to %s without an SSH username", host)
+	}
+
+	authMethods := []ssh.AuthMethod{}
+
+	if privateKey != "" {
+		var signer ssh.Signer
+		var err error
+
+		if privateKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(privateKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(privateKey))
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the SSH private key for %s - Reason: %s", host, err.Error())
+		}
+
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	}
+
+	if agentClient != nil {
+		authMethods = append(authMethods, ssh.PublicKeysCallback(agentClient.Signers))
+	}
+
+	if password != "" {
+		authMethods = append(authMethods, ssh.Password(password))
+	}
+
+	if len(authMethods) == 0 {
+		return nil, fmt.Errorf("Cannot connect to %s without an SSH password, private key or agent", host)
+	}
+
+	hostKeyCallback, hostKeyAlgorithms, err := c.getSSHHostKeyCallback(host, address, fingerprint, sshConfig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:              username,
+		Auth:              authMethods,
+		HostKeyAlgorithms: hostKeyAlgorithms,
+		HostKeyCallback:   hostKeyCallback,
+	}, nil
+}
+
+// getSSHHostKeyCallback returns the callback, which verifies the host key presented by a node or the jump host, along
+// with the host key algorithms matching the keys known for the host.
+func (c *VirtualEnvironmentClient) getSSHHostKeyCallback(host string, address string, fingerprint string, sshConfig *VirtualEnvironmentSSHConfiguration) (ssh.HostKeyCallback, []string, error) {
+	if fingerprint != "" {
+		expectedFingerprint := normalizeSSHFingerprint(fingerprint)
+
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			receivedFingerprint := ssh.FingerprintSHA256(key)
+
+			if receivedFingerprint != expectedFingerprint {
+				return fmt.Errorf(
+					"The SSH host key of %s (%s) does not match the pinned fingerprint (expected: %s, received: %s)",
+					host,
+					address,
+					expectedFingerprint,
+					receivedFingerprint,
+				)
+			}
+
+			return nil
+		}, nil, nil
+	}
+
+	if sshConfig.KnownHostsFile != "" {
+		return c.getSSHKnownHostsCallback(host, address, sshConfig)
+	}
+
+	if sshConfig.TrustOnFirstUse {
+		return nil, nil, fmt.Errorf("Cannot trust the SSH host key of %s (%s) on first use without a known hosts file to persist it in", host, address)
+	}
+
+	log.Printf("[WARN] The SSH host key of %s (%s) is not verified", host, address)
+
+	return ssh.InsecureIgnoreHostKey(), nil, nil
+}
+
+// getSSHKnownHostsCallback returns the callback, which verifies the host key presented by a node or the jump host
+// against a known_hosts file, along with the host key algorithms matching the keys known for the host.
+func (c *VirtualEnvironmentClient) getSSHKnownHostsCallback(host string, address string, sshConfig *VirtualEnvironmentSSHConfiguration) (ssh.HostKeyCallback, []string, error) {
+	knownHostsFile := sshConfig.KnownHostsFile
+
+	if strings.HasPrefix(knownHostsFile, "~/") {
+		homeDir, err := os.UserHomeDir()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		knownHostsFile = filepath.Join(homeDir, knownHostsFile[2:])
+	}
+
+	if sshConfig.TrustOnFirstUse {
+		err := os.MkdirAll(filepath.Dir(knownHostsFile), 0700)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		f, err := os.OpenFile(knownHostsFile, os.O_CREATE|os.O_RDONLY, 0600)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		f.Close()
+	}
+
+	knownHostsCallback, err := knownhosts.New(knownHostsFile)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to read the SSH known hosts from \"%s\" - Reason: %s", knownHostsFile, err.Error())
+	}
+
+	// Determine the types of the keys, which are known for the host, in order to prevent the server from presenting
+	// a key of another type.
+	hostKeyAlgorithms := []string{}
+	probeErr := knownHostsCallback(address, &net.TCPAddr{}, sshProbeKey{})
+	probeKeyErr := &knownhosts.KeyError{}
+
+	if errors.As(probeErr, &probeKeyErr) {
+		for _, k := range probeKeyErr.Want {
+			hostKeyAlgorithms = append(hostKeyAlgorithms, k.Key.Type())
+		}
+	}
+
+	sort.Strings(hostKeyAlgorithms)
+
+	if len(hostKeyAlgorithms) == 0 {
+		hostKeyAlgorithms = nil
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := knownHostsCallback(address, remote, key)
+		keyErr := &knownhosts.KeyError{}
+
+		if !errors.As(err, &keyErr) {
+			if err != nil {
+				return fmt.Errorf("Failed to verify the SSH host key of %s (%s) - Reason: %s", host, address, err.Error())
+			}
+
+			return nil
+		}
+
+		if len(keyErr.Want) > 0 {
+			return fmt.Errorf(
+				"The SSH host key of %s (%s) does not match the key in %s:%d (received: %s)",
+				host,
+				address,
+				keyErr.Want[0].Filename,
+				keyErr.Want[0].Line,
+				ssh.FingerprintSHA256(key),
+			)
+		}
+
+		if !sshConfig.TrustOnFirstUse {
+			return fmt.Errorf(
+				"The SSH host key of %s (%s) is not present in \"%s\" (received: %s)",
+				host,
+				address,
+				knownHostsFile,
+				ssh.FingerprintSHA256(key),
+			)
+		}
+
+		c.sshHostKeysMutex.Lock()
+		defer c.sshHostKeysMutex.Unlock()
+
+		log.Printf("[DEBUG] Adding SSH host key %s for %s (%s) to \"%s\"", ssh.FingerprintSHA256(key), host, address, knownHostsFile)
+
+		f, err := os.OpenFile(knownHostsFile, os.O_APPEND|os.O_WRONLY, 0600)
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		_, err = f.WriteString(knownhosts.Line([]string{address}, key) + "\n")
+
+		return err
+	}, hostKeyAlgorithms, nil
+}
+
+// getNodeSSHAddress returns the address of the SSH server on a node in the format host:port.
+func (c *VirtualEnvironmentClient) getNodeSSHAddress(nodeName string) (string, error) {
+	if c.SSH != nil {
+		if node, ok := c.SSH.Nodes[nodeName]; ok && node != nil && node.Address != "" {
+			port := node.Port
+
+			if port == 0 {
+				port = defaultSSHPort
+			}
+
+			return net.JoinHostPort(node.Address, strconv.Itoa(port)), nil
+		}
+	}
+
+	nodeAddress, err := c.GetNodeIP(nodeName)
+
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(*nodeAddress, strconv.Itoa(defaultSSHPort)), nil
+}
+
+// normalizeSSHFingerprint converts a SHA-256 fingerprint to the format returned by ssh.FingerprintSHA256.
+func normalizeSSHFingerprint(fingerprint string) string {
+	return "SHA256:" + strings.TrimRight(strings.TrimPrefix(fingerprint, "SHA256:"), "=")
+}
@@ -0,0 +1,64 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	defaultSSHPort = 22
+)
+
+// VirtualEnvironmentSSHConfiguration contains the settings for the SSH connections to the nodes.
+type VirtualEnvironmentSSHConfiguration struct {
+	Agent                bool
+	AgentSocket          string
+	HostKeyFingerprints  map[string]string
+	JumpHost             *VirtualEnvironmentSSHJumpHost
+	KnownHostsFile       string
+	Nodes                map[string]*VirtualEnvironmentSSHNode
+	Password             string
+	PrivateKey           string
+	PrivateKeyPassphrase string
+	TrustOnFirstUse      bool
+	Username             string
+}
+
+// VirtualEnvironmentSSHJumpHost contains the settings for the jump host, which tunnels the SSH connections to the nodes.
+type VirtualEnvironmentSSHJumpHost struct {
+	Address              string
+	HostKeyFingerprint   string
+	Password             string
+	PrivateKey           string
+	PrivateKeyPassphrase string
+	Username             string
+}
+
+// VirtualEnvironmentSSHNode contains the address of the SSH server on a node.
+type VirtualEnvironmentSSHNode struct {
+	Address string
+	Port    int
+}
+
+// sshProbeKey is a placeholder key, which never matches a known host key.
+type sshProbeKey struct{}
+
+// Marshal returns the serialized placeholder key.
+func (k sshProbeKey) Marshal() []byte {
+	return []byte(k.Type())
+}
+
+// Type returns the type of the placeholder key.
+func (k sshProbeKey) Type() string {
+	return "probe"
+}
+
+// Verify always fails as the placeholder key cannot verify signatures.
+func (k sshProbeKey) Verify(data []byte, sig *ssh.Signature) error {
+	return errors.New("Cannot verify signatures with a placeholder key")
+}
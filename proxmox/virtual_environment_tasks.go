@@ -0,0 +1,111 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewVirtualEnvironmentTask creates and initializes a VirtualEnvironmentTask instance from a unique process identifier.
+// The identifier has the format "UPID:node:pid:pstart:starttime:type:id:user:", where the numbers are hexadecimal.
+func NewVirtualEnvironmentTask(client *VirtualEnvironmentClient, upid string) (*VirtualEnvironmentTask, error) {
+	invalidErr := fmt.Errorf("The task identifier \"%s\" is invalid", upid)
+	upidParts := strings.Split(strings.TrimSuffix(upid, ":"), ":")
+
+	if len(upidParts) < 8 || upidParts[0] != "UPID" || upidParts[1] == "" {
+		return nil, invalidErr
+	}
+
+	pid, err := strconv.ParseInt(upidParts[2], 16, 64)
+
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	pstart, err := strconv.ParseInt(upidParts[3], 16, 64)
+
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	startTime, err := strconv.ParseInt(upidParts[4], 16, 64)
+
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	return &VirtualEnvironmentTask{
+		ID:        upidParts[6],
+		NodeName:  upidParts[1],
+		PID:       int(pid),
+		PStart:    int(pstart),
+		StartTime: time.Unix(startTime, 0),
+		Type:      upidParts[5],
+		UPID:      upid,
+		User:      strings.Join(upidParts[7:], ":"),
+		client:    client,
+	}, nil
+}
+
+// Log retrieves a range of lines from the task log.
+func (t *VirtualEnvironmentTask) Log(start int, limit int) ([]string, error) {
+	return t.LogContext(context.Background(), start, limit)
+}
+
+// LogContext retrieves a range of lines from the task log.
+func (t *VirtualEnvironmentTask) LogContext(ctx context.Context, start int, limit int) ([]string, error) {
+	return t.client.GetNodeTaskLogContext(ctx, t.NodeName, t.UPID, start, limit)
+}
+
+// Status retrieves the status of the task.
+func (t *VirtualEnvironmentTask) Status() (*VirtualEnvironmentNodeGetTaskStatusResponseData, error) {
+	return t.StatusContext(context.Background())
+}
+
+// StatusContext retrieves the status of the task.
+func (t *VirtualEnvironmentTask) StatusContext(ctx context.Context) (*VirtualEnvironmentNodeGetTaskStatusResponseData, error) {
+	return t.client.GetNodeTaskStatusContext(ctx, t.NodeName, t.UPID)
+}
+
+// Stop stops the task.
+func (t *VirtualEnvironmentTask) Stop() error {
+	return t.StopContext(context.Background())
+}
+
+// StopContext stops the task.
+func (t *VirtualEnvironmentTask) StopContext(ctx context.Context) error {
+	return t.client.StopNodeTaskContext(ctx, t.NodeName, t.UPID)
+}
+
+// Wait waits for the task to complete.
+func (t *VirtualEnvironmentTask) Wait(timeout int, delay int) error {
+	return t.WaitContext(context.Background(), timeout, delay)
+}
+
+// WaitContext waits for the task to complete or the context to be cancelled.
+func (t *VirtualEnvironmentTask) WaitContext(ctx context.Context, timeout int, delay int) error {
+	return t.client.WaitForNodeTaskContext(ctx, t.NodeName, t.UPID, timeout, delay)
+}
+
+// doTaskRequest performs a HTTP request, which spawns a task, and returns the task.
+func (c *VirtualEnvironmentClient) doTaskRequest(ctx context.Context, method, path string, requestBody interface{}) (*VirtualEnvironmentTask, error) {
+	resBody := &VirtualEnvironmentTaskResponseBody{}
+	err := c.DoRequestContext(ctx, method, path, requestBody, resBody)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resBody.Data == nil {
+		return nil, errors.New("The server did not include a data object in the response")
+	}
+
+	return NewVirtualEnvironmentTask(c, *resBody.Data)
+}
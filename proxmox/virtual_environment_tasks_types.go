@@ -0,0 +1,28 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"time"
+)
+
+// VirtualEnvironmentTask represents a task, which has been spawned on a node by an asynchronous operation.
+type VirtualEnvironmentTask struct {
+	ID        string
+	NodeName  string
+	PID       int
+	PStart    int
+	StartTime time.Time
+	Type      string
+	UPID      string
+	User      string
+
+	client *VirtualEnvironmentClient
+}
+
+// VirtualEnvironmentTaskResponseBody contains the body from a response, which identifies a spawned task.
+type VirtualEnvironmentTaskResponseBody struct {
+	Data *string `json:"data,omitempty"`
+}
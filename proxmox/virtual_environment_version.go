@@ -6,6 +6,8 @@ package proxmox
 
 import (
 	"errors"
+	"regexp"
+	"strconv"
 )
 
 // Version retrieves the version information.
@@ -23,3 +25,18 @@ func (c *VirtualEnvironmentClient) Version() (*VirtualEnvironmentVersionResponse
 
 	return resBody.Data, nil
 }
+
+// IsAtLeast determines whether the version is equal to or newer than the specified major and minor version.
+func (d *VirtualEnvironmentVersionResponseData) IsAtLeast(major int, minor int) bool {
+	r := regexp.MustCompile(`^(\d+)\.(\d+)`)
+	m := r.FindStringSubmatch(d.Version)
+
+	if m == nil {
+		return false
+	}
+
+	currentMajor, _ := strconv.Atoi(m[1])
+	currentMinor, _ := strconv.Atoi(m[2])
+
+	return currentMajor > major || (currentMajor == major && currentMinor >= minor)
+}
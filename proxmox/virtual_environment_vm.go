@@ -5,13 +5,13 @@
 package proxmox
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net/url"
 	"strings"
 	"sync"
-	"time"
 )
 
 const (
@@ -24,45 +24,34 @@ var (
 )
 
 // CloneVM clones a virtual machine.
-func (c *VirtualEnvironmentClient) CloneVM(nodeName string, vmID int, retries int, d *VirtualEnvironmentVMCloneRequestBody, timeout int) error {
-	resBody := &VirtualEnvironmentVMMoveDiskResponseBody{}
-	var err error
-
-	// just a guard in case someone sets retries to 0 unknowingly
-	if retries <= 0 {
-		retries = 1
-	}
-
-	for i := 0; i < retries; i++ {
-		err = c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/clone", url.PathEscape(nodeName), vmID), d, resBody)
-
-		if err != nil {
-			return err
-		}
-
-		if resBody.Data == nil {
-			return errors.New("The server did not include a data object in the response")
-		}
+func (c *VirtualEnvironmentClient) CloneVM(nodeName string, vmID int, d *VirtualEnvironmentVMCloneRequestBody, timeout int) error {
+	return c.CloneVMContext(context.Background(), nodeName, vmID, d, timeout)
+}
 
-		err = c.WaitForNodeTask(nodeName, *resBody.Data, timeout, 5)
+// CloneVMContext clones a virtual machine and aborts waiting for the clone task when the context is cancelled.
+func (c *VirtualEnvironmentClient) CloneVMContext(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMCloneRequestBody, timeout int) error {
+	task, err := c.CloneVMAsync(ctx, nodeName, vmID, d)
 
-		if err == nil {
-			return nil
-		}
-		time.Sleep(10 * time.Second)
+	if err != nil {
+		return err
 	}
 
-	return err
+	return task.WaitContext(ctx, timeout, 5)
 }
 
-// CreateVM creates a virtual machine.
-func (c *VirtualEnvironmentClient) CreateVM(nodeName string, d *VirtualEnvironmentVMCreateRequestBody) error {
-	return c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/qemu", url.PathEscape(nodeName)), d, nil)
+// CloneVMAsync clones a virtual machine asynchronously.
+func (c *VirtualEnvironmentClient) CloneVMAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMCloneRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/clone", url.PathEscape(nodeName), vmID), d)
 }
 
-// DeleteVM deletes a virtual machine.
-func (c *VirtualEnvironmentClient) DeleteVM(nodeName string, vmID int) error {
-	return c.DoRequest(hmDELETE, fmt.Sprintf("nodes/%s/qemu/%d", url.PathEscape(nodeName), vmID), nil, nil)
+// CreateVMAsync creates a virtual machine asynchronously.
+func (c *VirtualEnvironmentClient) CreateVMAsync(ctx context.Context, nodeName string, d *VirtualEnvironmentVMCreateRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu", url.PathEscape(nodeName)), d)
+}
+
+// DeleteVMAsync deletes a virtual machine asynchronously.
+func (c *VirtualEnvironmentClient) DeleteVMAsync(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmDELETE, fmt.Sprintf("nodes/%s/qemu/%d", url.PathEscape(nodeName), vmID), nil)
 }
 
 // GetVM retrieves a virtual machine.
@@ -127,8 +116,13 @@ func (c *VirtualEnvironmentClient) GetVMID() (*int, error) {
 
 // GetVMNetworkInterfacesFromAgent retrieves the network interfaces reported by the QEMU agent.
 func (c *VirtualEnvironmentClient) GetVMNetworkInterfacesFromAgent(nodeName string, vmID int) (*VirtualEnvironmentVMGetQEMUNetworkInterfacesResponseData, error) {
+	return c.GetVMNetworkInterfacesFromAgentContext(context.Background(), nodeName, vmID)
+}
+
+// GetVMNetworkInterfacesFromAgentContext retrieves the network interfaces reported by the QEMU agent.
+func (c *VirtualEnvironmentClient) GetVMNetworkInterfacesFromAgentContext(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentVMGetQEMUNetworkInterfacesResponseData, error) {
 	resBody := &VirtualEnvironmentVMGetQEMUNetworkInterfacesResponseBody{}
-	err := c.DoRequest(hmGET, fmt.Sprintf("nodes/%s/qemu/%d/agent/network-get-interfaces", url.PathEscape(nodeName), vmID), nil, resBody)
+	err := c.DoRequestContext(ctx, hmGET, fmt.Sprintf("nodes/%s/qemu/%d/agent/network-get-interfaces", url.PathEscape(nodeName), vmID), nil, resBody)
 
 	if err != nil {
 		return nil, err
@@ -143,8 +137,13 @@ func (c *VirtualEnvironmentClient) GetVMNetworkInterfacesFromAgent(nodeName stri
 
 // GetVMStatus retrieves the status for a virtual machine.
 func (c *VirtualEnvironmentClient) GetVMStatus(nodeName string, vmID int) (*VirtualEnvironmentVMGetStatusResponseData, error) {
+	return c.GetVMStatusContext(context.Background(), nodeName, vmID)
+}
+
+// GetVMStatusContext retrieves the status for a virtual machine.
+func (c *VirtualEnvironmentClient) GetVMStatusContext(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentVMGetStatusResponseData, error) {
 	resBody := &VirtualEnvironmentVMGetStatusResponseBody{}
-	err := c.DoRequest(hmGET, fmt.Sprintf("nodes/%s/qemu/%d/status/current", url.PathEscape(nodeName), vmID), nil, resBody)
+	err := c.DoRequestContext(ctx, hmGET, fmt.Sprintf("nodes/%s/qemu/%d/status/current", url.PathEscape(nodeName), vmID), nil, resBody)
 
 	if err != nil {
 		return nil, err
@@ -157,20 +156,20 @@ func (c *VirtualEnvironmentClient) GetVMStatus(nodeName string, vmID int) (*Virt
 	return resBody.Data, nil
 }
 
-// MoveVMDisk moves a virtual machine disk.
-func (c *VirtualEnvironmentClient) MoveVMDisk(nodeName string, vmID int, d *VirtualEnvironmentVMMoveDiskRequestBody, timeout int) error {
-	taskID, err := c.MoveVMDiskAsync(nodeName, vmID, d)
+// MigrateVM migrates a virtual machine.
+func (c *VirtualEnvironmentClient) MigrateVM(nodeName string, vmID int, d *VirtualEnvironmentVMMigrateRequestBody, timeout int) error {
+	return c.MigrateVMContext(context.Background(), nodeName, vmID, d, timeout)
+}
 
-	if strings.Contains(err.Error(), "you can't move to the same storage with same format") {
-		// if someone tries to move to the same storage, the move is considered to be successful
-		return nil
-	}
+// MigrateVMContext migrates a virtual machine and aborts waiting for the task when the context is cancelled.
+func (c *VirtualEnvironmentClient) MigrateVMContext(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMMigrateRequestBody, timeout int) error {
+	task, err := c.MigrateVMAsync(ctx, nodeName, vmID, d)
 
 	if err != nil {
 		return err
 	}
 
-	err = c.WaitForNodeTask(nodeName, *taskID, timeout, 5)
+	err = task.WaitContext(ctx, timeout, 5)
 
 	if err != nil {
 		return err
@@ -179,20 +178,41 @@ func (c *VirtualEnvironmentClient) MoveVMDisk(nodeName string, vmID int, d *Virt
 	return nil
 }
 
-// MoveVMDiskAsync moves a virtual machine disk asynchronously.
-func (c *VirtualEnvironmentClient) MoveVMDiskAsync(nodeName string, vmID int, d *VirtualEnvironmentVMMoveDiskRequestBody) (*string, error) {
-	resBody := &VirtualEnvironmentVMMoveDiskResponseBody{}
-	err := c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/move_disk", url.PathEscape(nodeName), vmID), d, resBody)
+// MigrateVMAsync migrates a virtual machine asynchronously.
+func (c *VirtualEnvironmentClient) MigrateVMAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMMigrateRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/migrate", url.PathEscape(nodeName), vmID), d)
+}
+
+// MoveVMDisk moves a virtual machine disk.
+func (c *VirtualEnvironmentClient) MoveVMDisk(nodeName string, vmID int, d *VirtualEnvironmentVMMoveDiskRequestBody, timeout int) error {
+	return c.MoveVMDiskContext(context.Background(), nodeName, vmID, d, timeout)
+}
+
+// MoveVMDiskContext moves a virtual machine disk and aborts waiting for the task when the context is cancelled.
+func (c *VirtualEnvironmentClient) MoveVMDiskContext(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMMoveDiskRequestBody, timeout int) error {
+	task, err := c.MoveVMDiskAsync(ctx, nodeName, vmID, d)
 
 	if err != nil {
-		return nil, err
+		// if someone tries to move to the same storage, the move is considered to be successful
+		if strings.Contains(err.Error(), "you can't move to the same storage with same format") {
+			return nil
+		}
+
+		return err
 	}
 
-	if resBody.Data == nil {
-		return nil, errors.New("The server did not include a data object in the response")
+	err = task.WaitContext(ctx, timeout, 5)
+
+	if err != nil {
+		return err
 	}
 
-	return resBody.Data, nil
+	return nil
+}
+
+// MoveVMDiskAsync moves a virtual machine disk asynchronously.
+func (c *VirtualEnvironmentClient) MoveVMDiskAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMMoveDiskRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/move_disk", url.PathEscape(nodeName), vmID), d)
 }
 
 // ListVMs retrieves a list of virtual machines.
@@ -202,13 +222,18 @@ func (c *VirtualEnvironmentClient) ListVMs() ([]*VirtualEnvironmentVMListRespons
 
 // RebootVM reboots a virtual machine.
 func (c *VirtualEnvironmentClient) RebootVM(nodeName string, vmID int, d *VirtualEnvironmentVMRebootRequestBody, timeout int) error {
-	taskID, err := c.RebootVMAsync(nodeName, vmID, d)
+	return c.RebootVMContext(context.Background(), nodeName, vmID, d, timeout)
+}
+
+// RebootVMContext reboots a virtual machine and aborts waiting for the task when the context is cancelled.
+func (c *VirtualEnvironmentClient) RebootVMContext(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMRebootRequestBody, timeout int) error {
+	task, err := c.RebootVMAsync(ctx, nodeName, vmID, d)
 
 	if err != nil {
 		return err
 	}
 
-	err = c.WaitForNodeTask(nodeName, *taskID, timeout, 5)
+	err = task.WaitContext(ctx, timeout, 5)
 
 	if err != nil {
 		return err
@@ -218,45 +243,31 @@ func (c *VirtualEnvironmentClient) RebootVM(nodeName string, vmID int, d *Virtua
 }
 
 // RebootVMAsync reboots a virtual machine asynchronously.
-func (c *VirtualEnvironmentClient) RebootVMAsync(nodeName string, vmID int, d *VirtualEnvironmentVMRebootRequestBody) (*string, error) {
-	resBody := &VirtualEnvironmentVMRebootResponseBody{}
-	err := c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/status/reboot", url.PathEscape(nodeName), vmID), d, resBody)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if resBody.Data == nil {
-		return nil, errors.New("The server did not include a data object in the response")
-	}
-
-	return resBody.Data, nil
+func (c *VirtualEnvironmentClient) RebootVMAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMRebootRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/status/reboot", url.PathEscape(nodeName), vmID), d)
 }
 
 // ResizeVMDisk resizes a virtual machine disk.
 func (c *VirtualEnvironmentClient) ResizeVMDisk(nodeName string, vmID int, d *VirtualEnvironmentVMResizeDiskRequestBody) error {
-	var err error
 	log.Printf("[DEBUG] RESIZE size: %s, disk: %s", d.Size, d.Disk)
-	for i := 0; i < 5; i++ {
-		err = c.DoRequest(hmPUT, fmt.Sprintf("nodes/%s/qemu/%d/resize", url.PathEscape(nodeName), vmID), d, nil)
-		if err == nil {
-			return nil
-		}
-		log.Printf("[DEBUG] resize disk failed, retry nr: %d", i)
-		time.Sleep(5 * time.Second)
-	}
-	return err
+
+	return c.DoRequest(hmPUT, fmt.Sprintf("nodes/%s/qemu/%d/resize", url.PathEscape(nodeName), vmID), d, nil)
 }
 
 // ShutdownVM shuts down a virtual machine.
 func (c *VirtualEnvironmentClient) ShutdownVM(nodeName string, vmID int, d *VirtualEnvironmentVMShutdownRequestBody, timeout int) error {
-	taskID, err := c.ShutdownVMAsync(nodeName, vmID, d)
+	return c.ShutdownVMContext(context.Background(), nodeName, vmID, d, timeout)
+}
+
+// ShutdownVMContext shuts down a virtual machine and aborts waiting for the task when the context is cancelled.
+func (c *VirtualEnvironmentClient) ShutdownVMContext(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMShutdownRequestBody, timeout int) error {
+	task, err := c.ShutdownVMAsync(ctx, nodeName, vmID, d)
 
 	if err != nil {
 		return err
 	}
 
-	err = c.WaitForNodeTask(nodeName, *taskID, timeout, 5)
+	err = task.WaitContext(ctx, timeout, 5)
 
 	if err != nil {
 		return err
@@ -266,30 +277,24 @@ func (c *VirtualEnvironmentClient) ShutdownVM(nodeName string, vmID int, d *Virt
 }
 
 // ShutdownVMAsync shuts down a virtual machine asynchronously.
-func (c *VirtualEnvironmentClient) ShutdownVMAsync(nodeName string, vmID int, d *VirtualEnvironmentVMShutdownRequestBody) (*string, error) {
-	resBody := &VirtualEnvironmentVMShutdownResponseBody{}
-	err := c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/status/shutdown", url.PathEscape(nodeName), vmID), d, resBody)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if resBody.Data == nil {
-		return nil, errors.New("The server did not include a data object in the response")
-	}
-
-	return resBody.Data, nil
+func (c *VirtualEnvironmentClient) ShutdownVMAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMShutdownRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/status/shutdown", url.PathEscape(nodeName), vmID), d)
 }
 
 // StartVM starts a virtual machine.
 func (c *VirtualEnvironmentClient) StartVM(nodeName string, vmID int, timeout int) error {
-	taskID, err := c.StartVMAsync(nodeName, vmID)
+	return c.StartVMContext(context.Background(), nodeName, vmID, timeout)
+}
+
+// StartVMContext starts a virtual machine and aborts waiting for the task when the context is cancelled.
+func (c *VirtualEnvironmentClient) StartVMContext(ctx context.Context, nodeName string, vmID int, timeout int) error {
+	task, err := c.StartVMAsync(ctx, nodeName, vmID)
 
 	if err != nil {
 		return err
 	}
 
-	err = c.WaitForNodeTask(nodeName, *taskID, timeout, 5)
+	err = task.WaitContext(ctx, timeout, 5)
 
 	if err != nil {
 		return err
@@ -299,30 +304,24 @@ func (c *VirtualEnvironmentClient) StartVM(nodeName string, vmID int, timeout in
 }
 
 // StartVMAsync starts a virtual machine asynchronously.
-func (c *VirtualEnvironmentClient) StartVMAsync(nodeName string, vmID int) (*string, error) {
-	resBody := &VirtualEnvironmentVMStartResponseBody{}
-	err := c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/status/start", url.PathEscape(nodeName), vmID), nil, resBody)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if resBody.Data == nil {
-		return nil, errors.New("The server did not include a data object in the response")
-	}
-
-	return resBody.Data, nil
+func (c *VirtualEnvironmentClient) StartVMAsync(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/status/start", url.PathEscape(nodeName), vmID), nil)
 }
 
 // StopVM stops a virtual machine.
 func (c *VirtualEnvironmentClient) StopVM(nodeName string, vmID int, timeout int) error {
-	taskID, err := c.StopVMAsync(nodeName, vmID)
+	return c.StopVMContext(context.Background(), nodeName, vmID, timeout)
+}
+
+// StopVMContext stops a virtual machine and aborts waiting for the task when the context is cancelled.
+func (c *VirtualEnvironmentClient) StopVMContext(ctx context.Context, nodeName string, vmID int, timeout int) error {
+	task, err := c.StopVMAsync(ctx, nodeName, vmID)
 
 	if err != nil {
 		return err
 	}
 
-	err = c.WaitForNodeTask(nodeName, *taskID, timeout, 5)
+	err = task.WaitContext(ctx, timeout, 5)
 
 	if err != nil {
 		return err
@@ -332,19 +331,8 @@ func (c *VirtualEnvironmentClient) StopVM(nodeName string, vmID int, timeout int
 }
 
 // StopVMAsync stops a virtual machine asynchronously.
-func (c *VirtualEnvironmentClient) StopVMAsync(nodeName string, vmID int) (*string, error) {
-	resBody := &VirtualEnvironmentVMStopResponseBody{}
-	err := c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/status/stop", url.PathEscape(nodeName), vmID), nil, resBody)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if resBody.Data == nil {
-		return nil, errors.New("The server did not include a data object in the response")
-	}
-
-	return resBody.Data, nil
+func (c *VirtualEnvironmentClient) StopVMAsync(ctx context.Context, nodeName string, vmID int) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/status/stop", url.PathEscape(nodeName), vmID), nil)
 }
 
 // UpdateVM updates a virtual machine.
@@ -353,148 +341,124 @@ func (c *VirtualEnvironmentClient) UpdateVM(nodeName string, vmID int, d *Virtua
 }
 
 // UpdateVMAsync updates a virtual machine asynchronously.
-func (c *VirtualEnvironmentClient) UpdateVMAsync(nodeName string, vmID int, d *VirtualEnvironmentVMUpdateRequestBody) (*string, error) {
-	resBody := &VirtualEnvironmentVMUpdateAsyncResponseBody{}
-	err := c.DoRequest(hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/config", url.PathEscape(nodeName), vmID), d, resBody)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if resBody.Data == nil {
-		return nil, errors.New("The server did not include a data object in the response")
-	}
-
-	return resBody.Data, nil
+func (c *VirtualEnvironmentClient) UpdateVMAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMUpdateRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/config", url.PathEscape(nodeName), vmID), d)
 }
 
 // WaitForNetworkInterfacesFromVMAgent waits for a virtual machine's QEMU agent to publish the network interfaces.
 func (c *VirtualEnvironmentClient) WaitForNetworkInterfacesFromVMAgent(nodeName string, vmID int, timeout int, delay int, waitForIP bool) (*VirtualEnvironmentVMGetQEMUNetworkInterfacesResponseData, error) {
-	timeDelay := int64(delay)
-	timeMax := float64(timeout)
-	timeStart := time.Now()
-	timeElapsed := timeStart.Sub(timeStart)
-
-	for timeElapsed.Seconds() < timeMax {
-		if int64(timeElapsed.Seconds())%timeDelay == 0 {
-			data, err := c.GetVMNetworkInterfacesFromAgent(nodeName, vmID)
-
-			if err == nil && data != nil && data.Result != nil {
-				missingIP := false
-
-				if waitForIP {
-					for _, nic := range *data.Result {
-						if nic.Name == "lo" {
-							continue
-						}
-
-						if nic.IPAddresses == nil || (nic.IPAddresses != nil && len(*nic.IPAddresses) == 0) {
-							missingIP = true
-							break
-						}
-					}
+	return c.WaitForNetworkInterfacesFromVMAgentContext(context.Background(), nodeName, vmID, timeout, delay, waitForIP)
+}
+
+// WaitForNetworkInterfacesFromVMAgentContext waits for a virtual machine's QEMU agent to publish the network interfaces or the context to be cancelled.
+func (c *VirtualEnvironmentClient) WaitForNetworkInterfacesFromVMAgentContext(ctx context.Context, nodeName string, vmID int, timeout int, delay int, waitForIP bool) (*VirtualEnvironmentVMGetQEMUNetworkInterfacesResponseData, error) {
+	var data *VirtualEnvironmentVMGetQEMUNetworkInterfacesResponseData
+
+	err := waitForCondition(ctx, timeout, delay, func() (bool, error) {
+		var err error
+
+		data, err = c.GetVMNetworkInterfacesFromAgentContext(ctx, nodeName, vmID)
+
+		if err != nil || data == nil || data.Result == nil {
+			return false, ctx.Err()
+		}
+
+		if waitForIP {
+			for _, nic := range *data.Result {
+				if nic.Name == "lo" {
+					continue
 				}
 
-				if !missingIP {
-					return data, err
+				if nic.IPAddresses == nil || (nic.IPAddresses != nil && len(*nic.IPAddresses) == 0) {
+					return false, nil
 				}
 			}
-
-			time.Sleep(1 * time.Second)
 		}
 
-		time.Sleep(200 * time.Millisecond)
+		return true, nil
+	})
 
-		timeElapsed = time.Now().Sub(timeStart)
+	if err == errWaitTimeout {
+		return nil, fmt.Errorf("Timeout while waiting for the QEMU agent on VM \"%d\" to publish the network interfaces", vmID)
 	}
 
-	return nil, fmt.Errorf("Timeout while waiting for the QEMU agent on VM \"%d\" to publish the network interfaces", vmID)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
 }
 
 // WaitForNoNetworkInterfacesFromVMAgent waits for a virtual machine's QEMU agent to unpublish the network interfaces.
 func (c *VirtualEnvironmentClient) WaitForNoNetworkInterfacesFromVMAgent(nodeName string, vmID int, timeout int, delay int) error {
-	timeDelay := int64(delay)
-	timeMax := float64(timeout)
-	timeStart := time.Now()
-	timeElapsed := timeStart.Sub(timeStart)
-
-	for timeElapsed.Seconds() < timeMax {
-		if int64(timeElapsed.Seconds())%timeDelay == 0 {
-			_, err := c.GetVMNetworkInterfacesFromAgent(nodeName, vmID)
-
-			if err != nil {
-				return nil
-			}
+	return c.WaitForNoNetworkInterfacesFromVMAgentContext(context.Background(), nodeName, vmID, timeout, delay)
+}
 
-			time.Sleep(1 * time.Second)
-		}
+// WaitForNoNetworkInterfacesFromVMAgentContext waits for a virtual machine's QEMU agent to unpublish the network interfaces or the context to be cancelled.
+func (c *VirtualEnvironmentClient) WaitForNoNetworkInterfacesFromVMAgentContext(ctx context.Context, nodeName string, vmID int, timeout int, delay int) error {
+	err := waitForCondition(ctx, timeout, delay, func() (bool, error) {
+		_, err := c.GetVMNetworkInterfacesFromAgentContext(ctx, nodeName, vmID)
 
-		time.Sleep(200 * time.Millisecond)
+		return err != nil, ctx.Err()
+	})
 
-		timeElapsed = time.Now().Sub(timeStart)
+	if err == errWaitTimeout {
+		return fmt.Errorf("Timeout while waiting for the QEMU agent on VM \"%d\" to unpublish the network interfaces", vmID)
 	}
 
-	return fmt.Errorf("Timeout while waiting for the QEMU agent on VM \"%d\" to unpublish the network interfaces", vmID)
+	return err
 }
 
 // WaitForVMConfigUnlock waits for a virtual machine configuration to become unlocked.
 func (c *VirtualEnvironmentClient) WaitForVMConfigUnlock(nodeName string, vmID int, timeout int, delay int, ignoreErrorResponse bool) error {
-	timeDelay := int64(delay)
-	timeMax := float64(timeout)
-	timeStart := time.Now()
-	timeElapsed := timeStart.Sub(timeStart)
-
-	for timeElapsed.Seconds() < timeMax {
-		if int64(timeElapsed.Seconds())%timeDelay == 0 {
-			data, err := c.GetVMStatus(nodeName, vmID)
-
-			if err != nil {
-				if !ignoreErrorResponse {
-					return err
-				}
-			} else if data.Lock == nil || *data.Lock == "" {
-				return nil
+	return c.WaitForVMConfigUnlockContext(context.Background(), nodeName, vmID, timeout, delay, ignoreErrorResponse)
+}
+
+// WaitForVMConfigUnlockContext waits for a virtual machine configuration to become unlocked or the context to be cancelled.
+func (c *VirtualEnvironmentClient) WaitForVMConfigUnlockContext(ctx context.Context, nodeName string, vmID int, timeout int, delay int, ignoreErrorResponse bool) error {
+	err := waitForCondition(ctx, timeout, delay, func() (bool, error) {
+		data, err := c.GetVMStatusContext(ctx, nodeName, vmID)
+
+		if err != nil {
+			if !ignoreErrorResponse || ctx.Err() != nil {
+				return false, err
 			}
 
-			time.Sleep(1 * time.Second)
+			return false, nil
 		}
 
-		time.Sleep(200 * time.Millisecond)
+		return data.Lock == nil || *data.Lock == "", nil
+	})
 
-		timeElapsed = time.Now().Sub(timeStart)
+	if err == errWaitTimeout {
+		return fmt.Errorf("Timeout while waiting for VM \"%d\" configuration to become unlocked", vmID)
 	}
 
-	return fmt.Errorf("Timeout while waiting for VM \"%d\" configuration to become unlocked", vmID)
+	return err
 }
 
 // WaitForVMState waits for a virtual machine to reach a specific state.
 func (c *VirtualEnvironmentClient) WaitForVMState(nodeName string, vmID int, state string, timeout int, delay int) error {
-	state = strings.ToLower(state)
-
-	timeDelay := int64(delay)
-	timeMax := float64(timeout)
-	timeStart := time.Now()
-	timeElapsed := timeStart.Sub(timeStart)
-
-	for timeElapsed.Seconds() < timeMax {
-		if int64(timeElapsed.Seconds())%timeDelay == 0 {
-			data, err := c.GetVMStatus(nodeName, vmID)
+	return c.WaitForVMStateContext(context.Background(), nodeName, vmID, state, timeout, delay)
+}
 
-			if err != nil {
-				return err
-			}
+// WaitForVMStateContext waits for a virtual machine to reach a specific state or the context to be cancelled.
+func (c *VirtualEnvironmentClient) WaitForVMStateContext(ctx context.Context, nodeName string, vmID int, state string, timeout int, delay int) error {
+	state = strings.ToLower(state)
 
-			if data.Status == state {
-				return nil
-			}
+	err := waitForCondition(ctx, timeout, delay, func() (bool, error) {
+		data, err := c.GetVMStatusContext(ctx, nodeName, vmID)
 
-			time.Sleep(1 * time.Second)
+		if err != nil {
+			return false, err
 		}
 
-		time.Sleep(200 * time.Millisecond)
+		return data.Status == state, nil
+	})
 
-		timeElapsed = time.Now().Sub(timeStart)
+	if err == errWaitTimeout {
+		return fmt.Errorf("Timeout while waiting for VM \"%d\" to enter the state \"%s\"", vmID, state)
 	}
 
-	return fmt.Errorf("Timeout while waiting for VM \"%d\" to enter the state \"%s\"", vmID, state)
+	return err
 }
@@ -0,0 +1,48 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// CreateVMSnapshotAsync creates a virtual machine snapshot asynchronously.
+func (c *VirtualEnvironmentClient) CreateVMSnapshotAsync(ctx context.Context, nodeName string, vmID int, d *VirtualEnvironmentVMSnapshotCreateRequestBody) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/snapshot", url.PathEscape(nodeName), vmID), d)
+}
+
+// DeleteVMSnapshotAsync deletes a virtual machine snapshot asynchronously.
+func (c *VirtualEnvironmentClient) DeleteVMSnapshotAsync(ctx context.Context, nodeName string, vmID int, snapshotName string) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmDELETE, fmt.Sprintf("nodes/%s/qemu/%d/snapshot/%s", url.PathEscape(nodeName), vmID, url.PathEscape(snapshotName)), nil)
+}
+
+// ListVMSnapshots retrieves a list of virtual machine snapshots.
+func (c *VirtualEnvironmentClient) ListVMSnapshots(nodeName string, vmID int) ([]*VirtualEnvironmentVMSnapshotListResponseData, error) {
+	resBody := &VirtualEnvironmentVMSnapshotListResponseBody{}
+	err := c.DoRequest(hmGET, fmt.Sprintf("nodes/%s/qemu/%d/snapshot", url.PathEscape(nodeName), vmID), nil, resBody)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resBody.Data == nil {
+		return nil, errors.New("The server did not include a data object in the response")
+	}
+
+	return resBody.Data, nil
+}
+
+// RollbackVMSnapshotAsync rolls back a virtual machine to a snapshot asynchronously.
+func (c *VirtualEnvironmentClient) RollbackVMSnapshotAsync(ctx context.Context, nodeName string, vmID int, snapshotName string) (*VirtualEnvironmentTask, error) {
+	return c.doTaskRequest(ctx, hmPOST, fmt.Sprintf("nodes/%s/qemu/%d/snapshot/%s/rollback", url.PathEscape(nodeName), vmID, url.PathEscape(snapshotName)), nil)
+}
+
+// UpdateVMSnapshot updates a virtual machine snapshot.
+func (c *VirtualEnvironmentClient) UpdateVMSnapshot(nodeName string, vmID int, snapshotName string, d *VirtualEnvironmentVMSnapshotUpdateRequestBody) error {
+	return c.DoRequest(hmPUT, fmt.Sprintf("nodes/%s/qemu/%d/snapshot/%s/config", url.PathEscape(nodeName), vmID, url.PathEscape(snapshotName)), d, nil)
+}
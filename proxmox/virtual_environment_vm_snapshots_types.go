@@ -0,0 +1,31 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmox
+
+// VirtualEnvironmentVMSnapshotCreateRequestBody contains the data for a VM snapshot create request.
+type VirtualEnvironmentVMSnapshotCreateRequestBody struct {
+	Description *string     `json:"description,omitempty" url:"description,omitempty"`
+	Name        string      `json:"snapname" url:"snapname"`
+	VMState     *CustomBool `json:"vmstate,omitempty" url:"vmstate,omitempty,int"`
+}
+
+// VirtualEnvironmentVMSnapshotListResponseBody contains the body from a VM snapshot list response.
+type VirtualEnvironmentVMSnapshotListResponseBody struct {
+	Data []*VirtualEnvironmentVMSnapshotListResponseData `json:"data,omitempty"`
+}
+
+// VirtualEnvironmentVMSnapshotListResponseData contains the data from a VM snapshot list response.
+type VirtualEnvironmentVMSnapshotListResponseData struct {
+	Description *string          `json:"description,omitempty"`
+	Name        string           `json:"name"`
+	Parent      *string          `json:"parent,omitempty"`
+	Time        *CustomTimestamp `json:"snaptime,omitempty"`
+	VMState     *CustomBool      `json:"vmstate,omitempty"`
+}
+
+// VirtualEnvironmentVMSnapshotUpdateRequestBody contains the data for a VM snapshot update request.
+type VirtualEnvironmentVMSnapshotUpdateRequestBody struct {
+	Description *string `json:"description,omitempty" url:"description"`
+}
@@ -70,9 +70,11 @@ type CustomCPUEmulation struct {
 
 // CustomEFIDisk handles QEMU EFI disk parameters.
 type CustomEFIDisk struct {
-	DiskSize   *int    `json:"size,omitempty" url:"size,omitempty"`
-	FileVolume string  `json:"file" url:"file"`
-	Format     *string `json:"format,omitempty" url:"format,omitempty"`
+	DiskSize        *int        `json:"size,omitempty" url:"size,omitempty"`
+	EFIType         *string     `json:"efitype,omitempty" url:"efitype,omitempty"`
+	FileVolume      string      `json:"file" url:"file"`
+	Format          *string     `json:"format,omitempty" url:"format,omitempty"`
+	PreEnrolledKeys *CustomBool `json:"pre-enrolled-keys,omitempty" url:"pre-enrolled-keys,omitempty,int"`
 }
 
 // CustomNetworkDevice handles QEMU network device parameters.
@@ -116,6 +118,13 @@ type CustomPCIDevice struct {
 // CustomPCIDevices handles QEMU host PCI device mapping parameters.
 type CustomPCIDevices []CustomPCIDevice
 
+// CustomRNGDevice handles QEMU VirtIO RNG device parameters.
+type CustomRNGDevice struct {
+	MaxBytes *int   `json:"max_bytes,omitempty" url:"max_bytes,omitempty"`
+	Period   *int   `json:"period,omitempty" url:"period,omitempty"`
+	Source   string `json:"source" url:"source"`
+}
+
 // CustomSerialDevices handles QEMU serial device parameters.
 type CustomSerialDevices []string
 
@@ -158,6 +167,7 @@ type CustomStorageDevice struct {
 	BurstableWriteSpeedMbps *int        `json:"mbps_wr_max,omitempty" url:"mbps_wr_max,omitempty"`
 	Enabled                 bool        `json:"-" url:"-"`
 	FileVolume              string      `json:"file" url:"file"`
+	ImportFrom              *string     `json:"import-from,omitempty" url:"import-from,omitempty"`
 	MaxReadSpeedMbps        *int        `json:"mbps_rd,omitempty" url:"mbps_rd,omitempty"`
 	MaxWriteSpeedMbps       *int        `json:"mbps_wr,omitempty" url:"mbps_wr,omitempty"`
 	Media                   *string     `json:"media,omitempty" url:"media,omitempty"`
@@ -172,6 +182,12 @@ type CustomStorageDevice struct {
 // CustomStorageDevices handles QEMU SATA device parameters.
 type CustomStorageDevices map[string]CustomStorageDevice
 
+// CustomTPMState handles QEMU TPM state parameters.
+type CustomTPMState struct {
+	FileVolume string  `json:"file" url:"file"`
+	Version    *string `json:"version,omitempty" url:"version,omitempty"`
+}
+
 // CustomUSBDevice handles QEMU USB device parameters.
 type CustomUSBDevice struct {
 	HostDevice string      `json:"host" url:"host"`
@@ -267,6 +283,7 @@ type VirtualEnvironmentVMCreateRequestBody struct {
 	PCIDevices           CustomPCIDevices             `json:"hostpci,omitempty" url:"hostpci,omitempty"`
 	PoolID               *string                      `json:"pool,omitempty" url:"pool,omitempty"`
 	Revert               *string                      `json:"revert,omitempty" url:"revert,omitempty"`
+	RNGDevice            *CustomRNGDevice             `json:"rng0,omitempty" url:"rng0,omitempty"`
 	SATADevices          CustomStorageDevices         `json:"sata,omitempty" url:"sata,omitempty"`
 	SCSIDevices          CustomStorageDevices         `json:"scsi,omitempty" url:"scsi,omitempty"`
 	SCSIHardware         *string                      `json:"scsihw,omitempty" url:"scsihw,omitempty"`
@@ -282,6 +299,7 @@ type VirtualEnvironmentVMCreateRequestBody struct {
 	Tags                 *string                      `json:"tags,omitempty" url:"tags,omitempty"`
 	Template             *CustomBool                  `json:"template,omitempty" url:"template,omitempty,int"`
 	TimeDriftFixEnabled  *CustomBool                  `json:"tdf,omitempty" url:"tdf,omitempty,int"`
+	TPMState             *CustomTPMState              `json:"tpmstate0,omitempty" url:"tpmstate0,omitempty"`
 	USBDevices           CustomUSBDevices             `json:"usb,omitempty" url:"usb,omitempty"`
 	VGADevice            *CustomVGADevice             `json:"vga,omitempty" url:"vga,omitempty"`
 	VirtualCPUCount      *int                         `json:"vcpus,omitempty" url:"vcpus,omitempty"`
@@ -399,13 +417,36 @@ type VirtualEnvironmentVMGetResponseData struct {
 	NetworkDevice5       *CustomNetworkDevice          `json:"net5,omitempty"`
 	NetworkDevice6       *CustomNetworkDevice          `json:"net6,omitempty"`
 	NetworkDevice7       *CustomNetworkDevice          `json:"net7,omitempty"`
-	NUMADevices          *CustomNUMADevices            `json:"numa_devices,omitempty"`
+	NUMADevice0          *CustomNUMADevice             `json:"numa0,omitempty"`
+	NUMADevice1          *CustomNUMADevice             `json:"numa1,omitempty"`
+	NUMADevice2          *CustomNUMADevice             `json:"numa2,omitempty"`
+	NUMADevice3          *CustomNUMADevice             `json:"numa3,omitempty"`
+	NUMADevice4          *CustomNUMADevice             `json:"numa4,omitempty"`
+	NUMADevice5          *CustomNUMADevice             `json:"numa5,omitempty"`
+	NUMADevice6          *CustomNUMADevice             `json:"numa6,omitempty"`
+	NUMADevice7          *CustomNUMADevice             `json:"numa7,omitempty"`
 	NUMAEnabled          *CustomBool                   `json:"numa,omitempty"`
 	OSType               *string                       `json:"ostype,omitempty"`
 	Overwrite            *CustomBool                   `json:"force,omitempty"`
-	PCIDevices           *CustomPCIDevices             `json:"hostpci,omitempty"`
+	PCIDevice0           *CustomPCIDevice              `json:"hostpci0,omitempty"`
+	PCIDevice1           *CustomPCIDevice              `json:"hostpci1,omitempty"`
+	PCIDevice2           *CustomPCIDevice              `json:"hostpci2,omitempty"`
+	PCIDevice3           *CustomPCIDevice              `json:"hostpci3,omitempty"`
+	PCIDevice4           *CustomPCIDevice              `json:"hostpci4,omitempty"`
+	PCIDevice5           *CustomPCIDevice              `json:"hostpci5,omitempty"`
+	PCIDevice6           *CustomPCIDevice              `json:"hostpci6,omitempty"`
+	PCIDevice7           *CustomPCIDevice              `json:"hostpci7,omitempty"`
+	PCIDevice8           *CustomPCIDevice              `json:"hostpci8,omitempty"`
+	PCIDevice9           *CustomPCIDevice              `json:"hostpci9,omitempty"`
+	PCIDevice10          *CustomPCIDevice              `json:"hostpci10,omitempty"`
+	PCIDevice11          *CustomPCIDevice              `json:"hostpci11,omitempty"`
+	PCIDevice12          *CustomPCIDevice              `json:"hostpci12,omitempty"`
+	PCIDevice13          *CustomPCIDevice              `json:"hostpci13,omitempty"`
+	PCIDevice14          *CustomPCIDevice              `json:"hostpci14,omitempty"`
+	PCIDevice15          *CustomPCIDevice              `json:"hostpci15,omitempty"`
 	PoolID               *string                       `json:"pool,omitempty" url:"pool,omitempty"`
 	Revert               *string                       `json:"revert,omitempty"`
+	RNGDevice            *CustomRNGDevice              `json:"rng0,omitempty"`
 	SATADevice0          *CustomStorageDevice          `json:"sata0,omitempty"`
 	SATADevice1          *CustomStorageDevice          `json:"sata1,omitempty"`
 	SATADevice2          *CustomStorageDevice          `json:"sata2,omitempty"`
@@ -442,7 +483,12 @@ type VirtualEnvironmentVMGetResponseData struct {
 	Tags                 *string                       `json:"tags,omitempty"`
 	Template             *CustomBool                   `json:"template,omitempty"`
 	TimeDriftFixEnabled  *CustomBool                   `json:"tdf,omitempty"`
-	USBDevices           *CustomUSBDevices             `json:"usb,omitempty"`
+	TPMState             *CustomTPMState               `json:"tpmstate0,omitempty"`
+	USBDevice0           *CustomUSBDevice              `json:"usb0,omitempty"`
+	USBDevice1           *CustomUSBDevice              `json:"usb1,omitempty"`
+	USBDevice2           *CustomUSBDevice              `json:"usb2,omitempty"`
+	USBDevice3           *CustomUSBDevice              `json:"usb3,omitempty"`
+	USBDevice4           *CustomUSBDevice              `json:"usb4,omitempty"`
 	VGADevice            *CustomVGADevice              `json:"vga,omitempty"`
 	VirtualCPUCount      *int                          `json:"vcpus,omitempty"`
 	VirtualIODevice0     *CustomStorageDevice          `json:"virtio0,omitempty"`
@@ -498,6 +544,14 @@ type VirtualEnvironmentVMListResponseData struct {
 	ACPI *CustomBool `json:"acpi,omitempty" url:"acpi,omitempty,int"`
 }
 
+// VirtualEnvironmentVMMigrateRequestBody contains the body for a VM migration request.
+type VirtualEnvironmentVMMigrateRequestBody struct {
+	OnlineMigration *CustomBool `json:"online,omitempty" url:"online,omitempty,int"`
+	TargetNode      string      `json:"target" url:"target"`
+	TargetStorage   *string     `json:"targetstorage,omitempty" url:"targetstorage,omitempty"`
+	WithLocalDisks  *CustomBool `json:"with-local-disks,omitempty" url:"with-local-disks,omitempty,int"`
+}
+
 // VirtualEnvironmentVMMoveDiskRequestBody contains the body for a VM move disk request.
 type VirtualEnvironmentVMMoveDiskRequestBody struct {
 	BandwidthLimit      *int        `json:"bwlimit,omitempty" url:"bwlimit,omitempty"`
@@ -508,21 +562,11 @@ type VirtualEnvironmentVMMoveDiskRequestBody struct {
 	TargetStorageFormat *string     `json:"format,omitempty" url:"format,omitempty"`
 }
 
-// VirtualEnvironmentVMMoveDiskResponseBody contains the body from a VM move disk response.
-type VirtualEnvironmentVMMoveDiskResponseBody struct {
-	Data *string `json:"data,omitempty"`
-}
-
 // VirtualEnvironmentVMRebootRequestBody contains the body for a VM reboot request.
 type VirtualEnvironmentVMRebootRequestBody struct {
 	Timeout *int `json:"timeout,omitempty" url:"timeout,omitempty"`
 }
 
-// VirtualEnvironmentVMRebootResponseBody contains the body from a VM reboot response.
-type VirtualEnvironmentVMRebootResponseBody struct {
-	Data *string `json:"data,omitempty"`
-}
-
 // VirtualEnvironmentVMResizeDiskRequestBody contains the body for a VM resize disk request.
 type VirtualEnvironmentVMResizeDiskRequestBody struct {
 	Digest   *string     `json:"digest,omitempty" url:"digest,omitempty"`
@@ -539,26 +583,6 @@ type VirtualEnvironmentVMShutdownRequestBody struct {
 	Timeout    *int        `json:"timeout,omitempty" url:"timeout,omitempty"`
 }
 
-// VirtualEnvironmentVMShutdownResponseBody contains the body from a VM shutdown response.
-type VirtualEnvironmentVMShutdownResponseBody struct {
-	Data *string `json:"data,omitempty"`
-}
-
-// VirtualEnvironmentVMStartResponseBody contains the body from a VM start response.
-type VirtualEnvironmentVMStartResponseBody struct {
-	Data *string `json:"data,omitempty"`
-}
-
-// VirtualEnvironmentVMStopResponseBody contains the body from a VM stop response.
-type VirtualEnvironmentVMStopResponseBody struct {
-	Data *string `json:"data,omitempty"`
-}
-
-// VirtualEnvironmentVMUpdateAsyncResponseBody contains the body from a VM async update response.
-type VirtualEnvironmentVMUpdateAsyncResponseBody struct {
-	Data *string `json:"data,omitempty"`
-}
-
 // VirtualEnvironmentVMUpdateRequestBody contains the data for an virtual machine update request.
 type VirtualEnvironmentVMUpdateRequestBody VirtualEnvironmentVMCreateRequestBody
 
@@ -723,10 +747,22 @@ func (r CustomEFIDisk) EncodeValues(key string, v *url.Values) error {
 		fmt.Sprintf("file=%s", r.FileVolume),
 	}
 
+	if r.EFIType != nil {
+		values = append(values, fmt.Sprintf("efitype=%s", *r.EFIType))
+	}
+
 	if r.Format != nil {
 		values = append(values, fmt.Sprintf("format=%s", *r.Format))
 	}
 
+	if r.PreEnrolledKeys != nil {
+		if *r.PreEnrolledKeys {
+			values = append(values, "pre-enrolled-keys=1")
+		} else {
+			values = append(values, "pre-enrolled-keys=0")
+		}
+	}
+
 	if r.DiskSize != nil {
 		values = append(values, fmt.Sprintf("size=%d", *r.DiskSize))
 	}
@@ -815,7 +851,7 @@ func (r CustomNUMADevice) EncodeValues(key string, v *url.Values) error {
 	}
 
 	if r.Memory != nil {
-		values = append(values, fmt.Sprintf("memory=%f", *r.Memory))
+		values = append(values, fmt.Sprintf("memory=%s", strconv.FormatFloat(*r.Memory, 'f', -1, 64)))
 	}
 
 	if r.Policy != nil {
@@ -888,6 +924,25 @@ func (r CustomPCIDevices) EncodeValues(key string, v *url.Values) error {
 	return nil
 }
 
+// EncodeValues converts a CustomRNGDevice struct to a URL vlaue.
+func (r CustomRNGDevice) EncodeValues(key string, v *url.Values) error {
+	values := []string{
+		fmt.Sprintf("source=%s", r.Source),
+	}
+
+	if r.MaxBytes != nil {
+		values = append(values, fmt.Sprintf("max_bytes=%d", *r.MaxBytes))
+	}
+
+	if r.Period != nil {
+		values = append(values, fmt.Sprintf("period=%d", *r.Period))
+	}
+
+	v.Add(key, strings.Join(values, ","))
+
+	return nil
+}
+
 // EncodeValues converts a CustomSerialDevices array to multiple URL values.
 func (r CustomSerialDevices) EncodeValues(key string, v *url.Values) error {
 	for i, d := range r {
@@ -1031,6 +1086,14 @@ func (r CustomStorageDevice) EncodeValues(key string, v *url.Values) error {
 		values = append(values, fmt.Sprintf("mbps_wr_max=%d", *r.BurstableWriteSpeedMbps))
 	}
 
+	if r.Format != nil {
+		values = append(values, fmt.Sprintf("format=%s", *r.Format))
+	}
+
+	if r.ImportFrom != nil {
+		values = append(values, fmt.Sprintf("import-from=%s", *r.ImportFrom))
+	}
+
 	if r.MaxReadSpeedMbps != nil {
 		values = append(values, fmt.Sprintf("mbps_rd=%d", *r.MaxReadSpeedMbps))
 	}
@@ -1063,6 +1126,21 @@ func (r CustomStorageDevices) EncodeValues(key string, v *url.Values) error {
 	return nil
 }
 
+// EncodeValues converts a CustomTPMState struct to a URL vlaue.
+func (r CustomTPMState) EncodeValues(key string, v *url.Values) error {
+	values := []string{
+		fmt.Sprintf("file=%s", r.FileVolume),
+	}
+
+	if r.Version != nil {
+		values = append(values, fmt.Sprintf("version=%s", *r.Version))
+	}
+
+	v.Add(key, strings.Join(values, ","))
+
+	return nil
+}
+
 // EncodeValues converts a CustomUSBDevice struct to a URL vlaue.
 func (r CustomUSBDevice) EncodeValues(key string, v *url.Values) error {
 	values := []string{
@@ -1352,6 +1430,41 @@ func (r *CustomCPUEmulation) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// UnmarshalJSON converts a CustomEFIDisk string to an object.
+func (r *CustomEFIDisk) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+
+	if err != nil {
+		return err
+	}
+
+	pairs := strings.Split(s, ",")
+
+	for _, p := range pairs {
+		v := strings.Split(strings.TrimSpace(p), "=")
+
+		if len(v) == 1 {
+			r.FileVolume = v[0]
+		} else if len(v) == 2 {
+			switch v[0] {
+			case "efitype":
+				r.EFIType = &v[1]
+			case "file":
+				r.FileVolume = v[1]
+			case "format":
+				r.Format = &v[1]
+			case "pre-enrolled-keys":
+				bv := CustomBool(v[1] == "1")
+				r.PreEnrolledKeys = &bv
+			}
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalJSON converts a CustomNetworkDevice string to an object.
 func (r *CustomNetworkDevice) UnmarshalJSON(b []byte) error {
 	var s string
@@ -1430,6 +1543,130 @@ func (r *CustomNetworkDevice) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// UnmarshalJSON converts a CustomNUMADevice string to an object.
+func (r *CustomNUMADevice) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+
+	if err != nil {
+		return err
+	}
+
+	pairs := strings.Split(s, ",")
+
+	for _, p := range pairs {
+		v := strings.Split(strings.TrimSpace(p), "=")
+
+		if len(v) == 2 {
+			switch v[0] {
+			case "cpus":
+				r.CPUIDs = strings.Split(v[1], ";")
+			case "hostnodes":
+				hostNodeNames := strings.Split(v[1], ";")
+				r.HostNodeNames = &hostNodeNames
+			case "memory":
+				fv, err := strconv.ParseFloat(v[1], 64)
+
+				if err != nil {
+					return err
+				}
+
+				r.Memory = &fv
+			case "policy":
+				r.Policy = &v[1]
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON converts a CustomPCIDevice string to an object.
+func (r *CustomPCIDevice) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+
+	if err != nil {
+		return err
+	}
+
+	pairs := strings.Split(s, ",")
+
+	for _, p := range pairs {
+		v := strings.Split(strings.TrimSpace(p), "=")
+
+		if len(v) == 1 {
+			r.DeviceIDs = strings.Split(v[0], ";")
+		} else if len(v) == 2 {
+			switch v[0] {
+			case "host":
+				r.DeviceIDs = strings.Split(v[1], ";")
+			case "mdev":
+				r.DevicePath = &v[1]
+			case "pcie":
+				bv := CustomBool(v[1] == "1")
+				r.PCIExpress = &bv
+			case "rombar":
+				bv := CustomBool(v[1] == "1")
+				r.ROMBAR = &bv
+			case "romfile":
+				r.ROMFile = &v[1]
+			case "x-vga":
+				bv := CustomBool(v[1] == "1")
+				r.XVGA = &bv
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON converts a CustomRNGDevice string to an object.
+func (r *CustomRNGDevice) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+
+	if err != nil {
+		return err
+	}
+
+	pairs := strings.Split(s, ",")
+
+	for _, p := range pairs {
+		v := strings.Split(strings.TrimSpace(p), "=")
+
+		if len(v) == 1 {
+			r.Source = v[0]
+		} else if len(v) == 2 {
+			switch v[0] {
+			case "max_bytes":
+				iv, err := strconv.Atoi(v[1])
+
+				if err != nil {
+					return err
+				}
+
+				r.MaxBytes = &iv
+			case "period":
+				iv, err := strconv.Atoi(v[1])
+
+				if err != nil {
+					return err
+				}
+
+				r.Period = &iv
+			case "source":
+				r.Source = v[1]
+			}
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalJSON converts a CustomSharedMemory string to an object.
 func (r *CustomSharedMemory) UnmarshalJSON(b []byte) error {
 	var s string
@@ -1475,7 +1712,7 @@ func (r *CustomSMBIOS) UnmarshalJSON(b []byte) error {
 	pairs := strings.Split(s, ",")
 
 	for _, p := range pairs {
-		v := strings.Split(strings.TrimSpace(p), "=")
+		v := strings.SplitN(strings.TrimSpace(p), "=", 2)
 
 		if len(v) == 2 {
 			switch v[0] {
@@ -1576,6 +1813,65 @@ func (r *CustomStorageDevice) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// UnmarshalJSON converts a CustomTPMState string to an object.
+func (r *CustomTPMState) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+
+	if err != nil {
+		return err
+	}
+
+	pairs := strings.Split(s, ",")
+
+	for _, p := range pairs {
+		v := strings.Split(strings.TrimSpace(p), "=")
+
+		if len(v) == 1 {
+			r.FileVolume = v[0]
+		} else if len(v) == 2 {
+			switch v[0] {
+			case "file":
+				r.FileVolume = v[1]
+			case "version":
+				r.Version = &v[1]
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON converts a CustomUSBDevice string to an object.
+func (r *CustomUSBDevice) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+
+	if err != nil {
+		return err
+	}
+
+	pairs := strings.Split(s, ",")
+
+	for _, p := range pairs {
+		v := strings.Split(strings.TrimSpace(p), "=")
+
+		if len(v) == 2 {
+			switch v[0] {
+			case "host":
+				r.HostDevice = v[1]
+			case "usb3":
+				bv := CustomBool(v[1] == "1")
+				r.USB3 = &bv
+			}
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalJSON converts a CustomVGADevice string to an object.
 func (r *CustomVGADevice) UnmarshalJSON(b []byte) error {
 	var s string
@@ -1615,3 +1911,33 @@ func (r *CustomVGADevice) UnmarshalJSON(b []byte) error {
 
 	return nil
 }
+
+// UnmarshalJSON converts a CustomWatchdogDevice string to an object.
+func (r *CustomWatchdogDevice) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+
+	if err != nil {
+		return err
+	}
+
+	pairs := strings.Split(s, ",")
+
+	for _, p := range pairs {
+		v := strings.Split(strings.TrimSpace(p), "=")
+
+		if len(v) == 1 {
+			r.Model = v[0]
+		} else if len(v) == 2 {
+			switch v[0] {
+			case "action":
+				r.Action = &v[1]
+			case "model":
+				r.Model = v[1]
+			}
+		}
+	}
+
+	return nil
+}
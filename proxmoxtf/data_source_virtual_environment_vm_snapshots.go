@@ -0,0 +1,166 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmoxtf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danitso/terraform-provider-proxmox/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const (
+	mkDataSourceVirtualEnvironmentVMSnapshotsCreationDates = "creation_dates"
+	mkDataSourceVirtualEnvironmentVMSnapshotsCurrentParent = "current_parent"
+	mkDataSourceVirtualEnvironmentVMSnapshotsDescriptions  = "descriptions"
+	mkDataSourceVirtualEnvironmentVMSnapshotsIncludeRAM    = "include_ram"
+	mkDataSourceVirtualEnvironmentVMSnapshotsNames         = "names"
+	mkDataSourceVirtualEnvironmentVMSnapshotsNodeName      = "node_name"
+	mkDataSourceVirtualEnvironmentVMSnapshotsParents       = "parents"
+	mkDataSourceVirtualEnvironmentVMSnapshotsVMID          = "vm_id"
+)
+
+func dataSourceVirtualEnvironmentVMSnapshots() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			mkDataSourceVirtualEnvironmentVMSnapshotsCreationDates: {
+				Type:        schema.TypeList,
+				Description: "The creation date of each snapshot",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			mkDataSourceVirtualEnvironmentVMSnapshotsCurrentParent: {
+				Type:        schema.TypeString,
+				Description: "The name of the snapshot, which the current state is based on",
+				Computed:    true,
+			},
+			mkDataSourceVirtualEnvironmentVMSnapshotsDescriptions: {
+				Type:        schema.TypeList,
+				Description: "The description of each snapshot",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			mkDataSourceVirtualEnvironmentVMSnapshotsIncludeRAM: {
+				Type:        schema.TypeList,
+				Description: "Whether a snapshot includes the RAM of the VM",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeBool},
+			},
+			mkDataSourceVirtualEnvironmentVMSnapshotsNames: {
+				Type:        schema.TypeList,
+				Description: "The snapshot names",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			mkDataSourceVirtualEnvironmentVMSnapshotsNodeName: {
+				Type:        schema.TypeString,
+				Description: "The node name",
+				Required:    true,
+			},
+			mkDataSourceVirtualEnvironmentVMSnapshotsParents: {
+				Type:        schema.TypeList,
+				Description: "The name of the parent snapshot of each snapshot",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			mkDataSourceVirtualEnvironmentVMSnapshotsVMID: {
+				Type:         schema.TypeInt,
+				Description:  "The VM identifier",
+				Required:     true,
+				ValidateFunc: getVMIDValidator(),
+			},
+		},
+		Read: dataSourceVirtualEnvironmentVMSnapshotsRead,
+	}
+}
+
+func dataSourceVirtualEnvironmentVMSnapshotsRead(d *schema.ResourceData, m interface{}) error {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return err
+	}
+
+	nodeName := d.Get(mkDataSourceVirtualEnvironmentVMSnapshotsNodeName).(string)
+	vmID := d.Get(mkDataSourceVirtualEnvironmentVMSnapshotsVMID).(int)
+	list, err := veClient.ListVMSnapshots(nodeName, vmID)
+
+	if err != nil {
+		return err
+	}
+
+	// The list includes an entry called "current", which represents the current state of the VM.
+	currentParent := ""
+	snapshots := []*proxmox.VirtualEnvironmentVMSnapshotListResponseData{}
+
+	for _, v := range list {
+		if v.Name == "current" {
+			if v.Parent != nil {
+				currentParent = *v.Parent
+			}
+
+			continue
+		}
+
+		snapshots = append(snapshots, v)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Time == nil || snapshots[j].Time == nil || time.Time(*snapshots[i].Time).Equal(time.Time(*snapshots[j].Time)) {
+			return snapshots[i].Name < snapshots[j].Name
+		}
+
+		return time.Time(*snapshots[i].Time).Before(time.Time(*snapshots[j].Time))
+	})
+
+	creationDates := make([]interface{}, len(snapshots))
+	descriptions := make([]interface{}, len(snapshots))
+	includeRAM := make([]interface{}, len(snapshots))
+	names := make([]interface{}, len(snapshots))
+	parents := make([]interface{}, len(snapshots))
+
+	for i, v := range snapshots {
+		if v.Time != nil {
+			creationDates[i] = time.Time(*v.Time).UTC().Format(time.RFC3339)
+		} else {
+			creationDates[i] = ""
+		}
+
+		if v.Description != nil {
+			descriptions[i] = strings.TrimSpace(*v.Description)
+		} else {
+			descriptions[i] = ""
+		}
+
+		if v.VMState != nil {
+			includeRAM[i] = bool(*v.VMState)
+		} else {
+			includeRAM[i] = false
+		}
+
+		names[i] = v.Name
+
+		if v.Parent != nil {
+			parents[i] = *v.Parent
+		} else {
+			parents[i] = ""
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s_%d_snapshots", nodeName, vmID))
+
+	d.Set(mkDataSourceVirtualEnvironmentVMSnapshotsCreationDates, creationDates)
+	d.Set(mkDataSourceVirtualEnvironmentVMSnapshotsCurrentParent, currentParent)
+	d.Set(mkDataSourceVirtualEnvironmentVMSnapshotsDescriptions, descriptions)
+	d.Set(mkDataSourceVirtualEnvironmentVMSnapshotsIncludeRAM, includeRAM)
+	d.Set(mkDataSourceVirtualEnvironmentVMSnapshotsNames, names)
+	d.Set(mkDataSourceVirtualEnvironmentVMSnapshotsParents, parents)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmoxtf
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// TestDataSourceVirtualEnvironmentVMSnapshotsInstantiation tests whether the DataSourceVirtualEnvironmentVMSnapshots instance can be instantiated.
+func TestDataSourceVirtualEnvironmentVMSnapshotsInstantiation(t *testing.T) {
+	s := dataSourceVirtualEnvironmentVMSnapshots()
+
+	if s == nil {
+		t.Fatalf("Cannot instantiate dataSourceVirtualEnvironmentVMSnapshots")
+	}
+}
+
+// TestDataSourceVirtualEnvironmentVMSnapshotsSchema tests the dataSourceVirtualEnvironmentVMSnapshots schema.
+func TestDataSourceVirtualEnvironmentVMSnapshotsSchema(t *testing.T) {
+	s := dataSourceVirtualEnvironmentVMSnapshots()
+
+	testRequiredArguments(t, s, []string{
+		mkDataSourceVirtualEnvironmentVMSnapshotsNodeName,
+		mkDataSourceVirtualEnvironmentVMSnapshotsVMID,
+	})
+
+	testComputedAttributes(t, s, []string{
+		mkDataSourceVirtualEnvironmentVMSnapshotsCreationDates,
+		mkDataSourceVirtualEnvironmentVMSnapshotsCurrentParent,
+		mkDataSourceVirtualEnvironmentVMSnapshotsDescriptions,
+		mkDataSourceVirtualEnvironmentVMSnapshotsIncludeRAM,
+		mkDataSourceVirtualEnvironmentVMSnapshotsNames,
+		mkDataSourceVirtualEnvironmentVMSnapshotsParents,
+	})
+
+	testValueTypes(t, s, map[string]schema.ValueType{
+		mkDataSourceVirtualEnvironmentVMSnapshotsCreationDates: schema.TypeList,
+		mkDataSourceVirtualEnvironmentVMSnapshotsCurrentParent: schema.TypeString,
+		mkDataSourceVirtualEnvironmentVMSnapshotsDescriptions:  schema.TypeList,
+		mkDataSourceVirtualEnvironmentVMSnapshotsIncludeRAM:    schema.TypeList,
+		mkDataSourceVirtualEnvironmentVMSnapshotsNames:         schema.TypeList,
+		mkDataSourceVirtualEnvironmentVMSnapshotsNodeName:      schema.TypeString,
+		mkDataSourceVirtualEnvironmentVMSnapshotsParents:       schema.TypeList,
+		mkDataSourceVirtualEnvironmentVMSnapshotsVMID:          schema.TypeInt,
+	})
+}
@@ -5,36 +5,86 @@
 package proxmoxtf
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/url"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/danitso/terraform-provider-proxmox/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 const (
-	dvProviderVirtualEnvironmentEndpoint = ""
-	dvProviderVirtualEnvironmentOTP      = ""
-	dvProviderVirtualEnvironmentPassword = ""
-	dvProviderVirtualEnvironmentUsername = ""
-
-	mkProviderVirtualEnvironment         = "virtual_environment"
-	mkProviderVirtualEnvironmentEndpoint = "endpoint"
-	mkProviderVirtualEnvironmentInsecure = "insecure"
-	mkProviderVirtualEnvironmentOTP      = "otp"
-	mkProviderVirtualEnvironmentPassword = "password"
-	mkProviderVirtualEnvironmentUsername = "username"
+	dvProviderVirtualEnvironmentAPIToken          = ""
+	dvProviderVirtualEnvironmentCAFile            = ""
+	dvProviderVirtualEnvironmentCAPEM             = ""
+	dvProviderVirtualEnvironmentDiscoverEndpoints = false
+	dvProviderVirtualEnvironmentEndpoint          = ""
+	dvProviderVirtualEnvironmentMaxRetries        = 3
+	dvProviderVirtualEnvironmentOTP               = ""
+	dvProviderVirtualEnvironmentPassword          = ""
+	dvProviderVirtualEnvironmentRetryDelayMax     = "30s"
+	dvProviderVirtualEnvironmentRetryDelayMin     = "1s"
+	dvProviderVirtualEnvironmentSSHAgent          = false
+	dvProviderVirtualEnvironmentSSHAgentSocket    = ""
+	dvProviderVirtualEnvironmentSSHJumpHostKey    = ""
+	dvProviderVirtualEnvironmentSSHKnownHostsFile = ""
+	dvProviderVirtualEnvironmentSSHNodePort       = 22
+	dvProviderVirtualEnvironmentSSHPassword       = ""
+	dvProviderVirtualEnvironmentSSHPrivateKey     = ""
+	dvProviderVirtualEnvironmentSSHPrivateKeyPass = ""
+	dvProviderVirtualEnvironmentSSHTOFU           = false
+	dvProviderVirtualEnvironmentSSHUsername       = ""
+	dvProviderVirtualEnvironmentUsername          = ""
+
+	mkProviderVirtualEnvironment                  = "virtual_environment"
+	mkProviderVirtualEnvironmentAPIToken          = "api_token"
+	mkProviderVirtualEnvironmentCAFile            = "ca_file"
+	mkProviderVirtualEnvironmentCAPEM             = "ca_pem"
+	mkProviderVirtualEnvironmentDiscoverEndpoints = "discover_endpoints"
+	mkProviderVirtualEnvironmentEndpoint          = "endpoint"
+	mkProviderVirtualEnvironmentEndpoints         = "endpoints"
+	mkProviderVirtualEnvironmentInsecure          = "insecure"
+	mkProviderVirtualEnvironmentMaxRetries        = "max_retries"
+	mkProviderVirtualEnvironmentOTP               = "otp"
+	mkProviderVirtualEnvironmentPassword          = "password"
+	mkProviderVirtualEnvironmentRetryDelayMax     = "retry_delay_max"
+	mkProviderVirtualEnvironmentRetryDelayMin     = "retry_delay_min"
+	mkProviderVirtualEnvironmentSSH               = "ssh"
+	mkProviderVirtualEnvironmentSSHAgent          = "agent"
+	mkProviderVirtualEnvironmentSSHAgentSocket    = "agent_socket"
+	mkProviderVirtualEnvironmentSSHHostKeys       = "host_key_fingerprints"
+	mkProviderVirtualEnvironmentSSHJumpHost       = "jump_host"
+	mkProviderVirtualEnvironmentSSHJumpHostAddr   = "address"
+	mkProviderVirtualEnvironmentSSHJumpHostKey    = "host_key_fingerprint"
+	mkProviderVirtualEnvironmentSSHKnownHostsFile = "known_hosts_file"
+	mkProviderVirtualEnvironmentSSHNode           = "node"
+	mkProviderVirtualEnvironmentSSHNodeAddress    = "address"
+	mkProviderVirtualEnvironmentSSHNodeName       = "name"
+	mkProviderVirtualEnvironmentSSHNodePort       = "port"
+	mkProviderVirtualEnvironmentSSHPassword       = "password"
+	mkProviderVirtualEnvironmentSSHPrivateKey     = "private_key"
+	mkProviderVirtualEnvironmentSSHPrivateKeyPass = "private_key_passphrase"
+	mkProviderVirtualEnvironmentSSHTOFU           = "trust_on_first_use"
+	mkProviderVirtualEnvironmentSSHUsername       = "username"
+	mkProviderVirtualEnvironmentTLSFingerprints   = "tls_fingerprints"
+	mkProviderVirtualEnvironmentUsername          = "username"
 )
 
 type providerConfiguration struct {
-	veClient *proxmox.VirtualEnvironmentClient
+	stopContext context.Context
+	veClient    *proxmox.VirtualEnvironmentClient
 }
 
 // Provider returns the object for this provider.
 func Provider() *schema.Provider {
-	return &schema.Provider{
-		ConfigureFunc: providerConfigure,
+	provider := &schema.Provider{
 		DataSourcesMap: map[string]*schema.Resource{
 			"proxmox_virtual_environment_cluster_alias":   dataSourceVirtualEnvironmentClusterAlias(),
 			"proxmox_virtual_environment_cluster_aliases": dataSourceVirtualEnvironmentClusterAliases(),
@@ -52,6 +102,7 @@ func Provider() *schema.Provider {
 			"proxmox_virtual_environment_user":            dataSourceVirtualEnvironmentUser(),
 			"proxmox_virtual_environment_users":           dataSourceVirtualEnvironmentUsers(),
 			"proxmox_virtual_environment_version":         dataSourceVirtualEnvironmentVersion(),
+			"proxmox_virtual_environment_vm_snapshots":    dataSourceVirtualEnvironmentVMSnapshots(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"proxmox_virtual_environment_certificate":   resourceVirtualEnvironmentCertificate(),
@@ -67,6 +118,7 @@ func Provider() *schema.Provider {
 			"proxmox_virtual_environment_time":          resourceVirtualEnvironmentTime(),
 			"proxmox_virtual_environment_user":          resourceVirtualEnvironmentUser(),
 			"proxmox_virtual_environment_vm":            resourceVirtualEnvironmentVM(),
+			"proxmox_virtual_environment_vm_snapshot":   resourceVirtualEnvironmentVMSnapshot(),
 		},
 		Schema: map[string]*schema.Schema{
 			mkProviderVirtualEnvironment: {
@@ -74,6 +126,57 @@ func Provider() *schema.Provider {
 				Optional: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						mkProviderVirtualEnvironmentAPIToken: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The API token for the Proxmox Virtual Environment API",
+							DefaultFunc: schema.MultiEnvDefaultFunc(
+								[]string{"PROXMOX_VE_API_TOKEN", "PM_VE_API_TOKEN"},
+								dvProviderVirtualEnvironmentAPIToken,
+							),
+							ValidateFunc: func(v interface{}, k string) (warns []string, errs []error) {
+								value := v.(string)
+
+								if value == "" {
+									return []string{}, []error{}
+								}
+
+								r := regexp.MustCompile(`^[^\s@!=]+@[^\s@!=]+![^\s@!=]+=\S+$`)
+
+								if !r.MatchString(value) {
+									return []string{}, []error{
+										errors.New("You must specify a valid API token for the Proxmox Virtual Environment API (valid: username@realm!tokenid=secret)"),
+									}
+								}
+
+								return []string{}, []error{}
+							},
+						},
+						mkProviderVirtualEnvironmentCAFile: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The path to a file containing PEM encoded CA certificates for verifying the API endpoint",
+							DefaultFunc: schema.MultiEnvDefaultFunc(
+								[]string{"PROXMOX_VE_CA_FILE", "PM_VE_CA_FILE"},
+								dvProviderVirtualEnvironmentCAFile,
+							),
+						},
+						mkProviderVirtualEnvironmentCAPEM: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The PEM encoded CA certificates for verifying the API endpoint",
+							DefaultFunc: schema.MultiEnvDefaultFunc(
+								[]string{"PROXMOX_VE_CA_PEM", "PM_VE_CA_PEM"},
+								dvProviderVirtualEnvironmentCAPEM,
+							),
+						},
+						mkProviderVirtualEnvironmentDiscoverEndpoints: {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Whether to discover the addresses of the cluster nodes and use them as additional endpoints",
+							Default:     dvProviderVirtualEnvironmentDiscoverEndpoints,
+						},
 						mkProviderVirtualEnvironmentEndpoint: {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -102,6 +205,25 @@ func Provider() *schema.Provider {
 								return []string{}, []error{}
 							},
 						},
+						mkProviderVirtualEnvironmentEndpoints: {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "The additional endpoints for the Proxmox Virtual Environment API, which are used when the primary endpoint is unavailable",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+								ValidateFunc: func(v interface{}, k string) (warns []string, errs []error) {
+									_, err := url.ParseRequestURI(v.(string))
+
+									if err != nil {
+										return []string{}, []error{
+											errors.New("You must specify a valid endpoint for the Proxmox Virtual Environment API (valid: https://host:port)"),
+										}
+									}
+
+									return []string{}, []error{}
+								},
+							},
+						},
 						mkProviderVirtualEnvironmentInsecure: {
 							Type:        schema.TypeBool,
 							Optional:    true,
@@ -118,6 +240,13 @@ func Provider() *schema.Provider {
 								return false, nil
 							},
 						},
+						mkProviderVirtualEnvironmentMaxRetries: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "The maximum number of times to retry requests, which have failed due to transient errors",
+							Default:      dvProviderVirtualEnvironmentMaxRetries,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
 						mkProviderVirtualEnvironmentOTP: {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -135,16 +264,225 @@ func Provider() *schema.Provider {
 								[]string{"PROXMOX_VE_PASSWORD", "PM_VE_PASSWORD"},
 								dvProviderVirtualEnvironmentPassword,
 							),
-							ValidateFunc: func(v interface{}, k string) (warns []string, errs []error) {
-								value := v.(string)
+						},
+						mkProviderVirtualEnvironmentRetryDelayMax: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "The maximum delay between retries",
+							Default:      dvProviderVirtualEnvironmentRetryDelayMax,
+							ValidateFunc: getTimeoutValidator(),
+						},
+						mkProviderVirtualEnvironmentRetryDelayMin: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "The minimum delay between retries",
+							Default:      dvProviderVirtualEnvironmentRetryDelayMin,
+							ValidateFunc: getTimeoutValidator(),
+						},
+						mkProviderVirtualEnvironmentSSH: {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "The SSH connection settings for the nodes",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									mkProviderVirtualEnvironmentSSHAgent: {
+										Type:        schema.TypeBool,
+										Optional:    true,
+										Description: "Whether to authenticate with the keys held by the SSH agent",
+										DefaultFunc: func() (interface{}, error) {
+											for _, k := range []string{"PROXMOX_VE_SSH_AGENT", "PM_VE_SSH_AGENT"} {
+												v := os.Getenv(k)
 
-								if value == "" {
-									return []string{}, []error{
-										errors.New("You must specify a password for the Proxmox Virtual Environment API"),
+												if v == "true" || v == "1" {
+													return true, nil
+												}
+											}
+
+											return dvProviderVirtualEnvironmentSSHAgent, nil
+										},
+									},
+									mkProviderVirtualEnvironmentSSHAgentSocket: {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "The path to the socket of the SSH agent",
+										DefaultFunc: schema.MultiEnvDefaultFunc(
+											[]string{"SSH_AUTH_SOCK", "PROXMOX_VE_SSH_AUTH_SOCK", "PM_VE_SSH_AUTH_SOCK"},
+											dvProviderVirtualEnvironmentSSHAgentSocket,
+										),
+									},
+									mkProviderVirtualEnvironmentSSHHostKeys: {
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Description: "The SHA-256 fingerprints of the SSH host keys indexed by node name",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										ValidateFunc: func(v interface{}, k string) (warns []string, errs []error) {
+											r := regexp.MustCompile(`^(SHA256:)?[A-Za-z0-9+/]{43}=?$`)
+
+											for nodeName, fingerprint := range v.(map[string]interface{}) {
+												if !r.MatchString(fingerprint.(string)) {
+													errs = append(errs, fmt.Errorf("You must specify a valid SHA-256 fingerprint for the SSH host key of node \"%s\" (valid: SHA256:base64)", nodeName))
+												}
+											}
+
+											return []string{}, errs
+										},
+									},
+									mkProviderVirtualEnvironmentSSHJumpHost: {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "The jump host, which tunnels the SSH connections to the nodes",
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												mkProviderVirtualEnvironmentSSHJumpHostAddr: {
+													Type:         schema.TypeString,
+													Required:     true,
+													Description:  "The address of the jump host in the format host[:port]",
+													ValidateFunc: validation.NoZeroValues,
+												},
+												mkProviderVirtualEnvironmentSSHJumpHostKey: {
+													Type:        schema.TypeString,
+													Optional:    true,
+													Description: "The SHA-256 fingerprint of the SSH host key of the jump host",
+													Default:     dvProviderVirtualEnvironmentSSHJumpHostKey,
+													ValidateFunc: validation.StringMatch(
+														regexp.MustCompile(`^((SHA256:)?[A-Za-z0-9+/]{43}=?)?$`),
+														"You must specify a valid SHA-256 fingerprint for the SSH host key of the jump host (valid: SHA256:base64)",
+													),
+												},
+												mkProviderVirtualEnvironmentSSHPassword: {
+													Type:        schema.TypeString,
+													Optional:    true,
+													Sensitive:   true,
+													Description: "The password for the jump host",
+													Default:     dvProviderVirtualEnvironmentSSHPassword,
+												},
+												mkProviderVirtualEnvironmentSSHPrivateKey: {
+													Type:        schema.TypeString,
+													Optional:    true,
+													Sensitive:   true,
+													Description: "The PEM encoded private key for the jump host",
+													Default:     dvProviderVirtualEnvironmentSSHPrivateKey,
+												},
+												mkProviderVirtualEnvironmentSSHPrivateKeyPass: {
+													Type:        schema.TypeString,
+													Optional:    true,
+													Sensitive:   true,
+													Description: "The passphrase for the private key",
+													Default:     dvProviderVirtualEnvironmentSSHPrivateKeyPass,
+												},
+												mkProviderVirtualEnvironmentSSHUsername: {
+													Type:        schema.TypeString,
+													Optional:    true,
+													Description: "The username for the jump host",
+													Default:     dvProviderVirtualEnvironmentSSHUsername,
+												},
+											},
+										},
+										MaxItems: 1,
+									},
+									mkProviderVirtualEnvironmentSSHKnownHostsFile: {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "The path to the known_hosts file used to verify the SSH host keys",
+										DefaultFunc: schema.MultiEnvDefaultFunc(
+											[]string{"PROXMOX_VE_SSH_KNOWN_HOSTS_FILE", "PM_VE_SSH_KNOWN_HOSTS_FILE"},
+											dvProviderVirtualEnvironmentSSHKnownHostsFile,
+										),
+									},
+									mkProviderVirtualEnvironmentSSHNode: {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "The SSH addresses of the nodes",
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												mkProviderVirtualEnvironmentSSHNodeAddress: {
+													Type:         schema.TypeString,
+													Required:     true,
+													Description:  "The address of the SSH server on the node",
+													ValidateFunc: validation.NoZeroValues,
+												},
+												mkProviderVirtualEnvironmentSSHNodeName: {
+													Type:         schema.TypeString,
+													Required:     true,
+													Description:  "The node name",
+													ValidateFunc: validation.NoZeroValues,
+												},
+												mkProviderVirtualEnvironmentSSHNodePort: {
+													Type:         schema.TypeInt,
+													Optional:     true,
+													Description:  "The port of the SSH server on the node",
+													Default:      dvProviderVirtualEnvironmentSSHNodePort,
+													ValidateFunc: validation.IsPortNumber,
+												},
+											},
+										},
+									},
+									mkProviderVirtualEnvironmentSSHPassword: {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Sensitive:   true,
+										Description: "The password for the SSH connections",
+										DefaultFunc: schema.MultiEnvDefaultFunc(
+											[]string{"PROXMOX_VE_SSH_PASSWORD", "PM_VE_SSH_PASSWORD"},
+											dvProviderVirtualEnvironmentSSHPassword,
+										),
+									},
+									mkProviderVirtualEnvironmentSSHPrivateKey: {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Sensitive:   true,
+										Description: "The PEM encoded private key for the SSH connections",
+										DefaultFunc: schema.MultiEnvDefaultFunc(
+											[]string{"PROXMOX_VE_SSH_PRIVATE_KEY", "PM_VE_SSH_PRIVATE_KEY"},
+											dvProviderVirtualEnvironmentSSHPrivateKey,
+										),
+									},
+									mkProviderVirtualEnvironmentSSHPrivateKeyPass: {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Sensitive:   true,
+										Description: "The passphrase for the private key",
+										DefaultFunc: schema.MultiEnvDefaultFunc(
+											[]string{"PROXMOX_VE_SSH_PRIVATE_KEY_PASSPHRASE", "PM_VE_SSH_PRIVATE_KEY_PASSPHRASE"},
+											dvProviderVirtualEnvironmentSSHPrivateKeyPass,
+										),
+									},
+									mkProviderVirtualEnvironmentSSHTOFU: {
+										Type:        schema.TypeBool,
+										Optional:    true,
+										Description: "Whether to trust the SSH host keys of unknown nodes on first use",
+										Default:     dvProviderVirtualEnvironmentSSHTOFU,
+									},
+									mkProviderVirtualEnvironmentSSHUsername: {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "The username for the SSH connections",
+										DefaultFunc: schema.MultiEnvDefaultFunc(
+											[]string{"PROXMOX_VE_SSH_USERNAME", "PM_VE_SSH_USERNAME"},
+											dvProviderVirtualEnvironmentSSHUsername,
+										),
+									},
+								},
+							},
+							MaxItems: 1,
+						},
+						mkProviderVirtualEnvironmentTLSFingerprints: {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "The SHA-256 fingerprints of the certificates presented by the API endpoints",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+								ValidateFunc: func(v interface{}, k string) (warns []string, errs []error) {
+									r := regexp.MustCompile(`^([0-9A-Fa-f]{2}:){31}[0-9A-Fa-f]{2}$`)
+
+									if !r.MatchString(v.(string)) {
+										return []string{}, []error{
+											errors.New("You must specify a valid SHA-256 fingerprint for the Proxmox Virtual Environment API (valid: AA:BB:...:FF)"),
+										}
 									}
-								}
 
-								return []string{}, []error{}
+									return []string{}, []error{}
+								},
 							},
 						},
 						mkProviderVirtualEnvironmentUsername: {
@@ -158,9 +496,10 @@ func Provider() *schema.Provider {
 							ValidateFunc: func(v interface{}, k string) (warns []string, errs []error) {
 								value := v.(string)
 
-								if value == "" {
+								// The username is optional, as an API token may be specified instead.
+								if value != "" && !strings.Contains(value, "@") {
 									return []string{}, []error{
-										errors.New("You must specify a username for the Proxmox Virtual Environment API (valid: username@realm)"),
+										errors.New("You must specify a valid username for the Proxmox Virtual Environment API (valid: username@realm)"),
 									}
 								}
 
@@ -173,9 +512,15 @@ func Provider() *schema.Provider {
 			},
 		},
 	}
+
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(provider.StopContext(), d)
+	}
+
+	return provider
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(stopContext context.Context, d *schema.ResourceData) (interface{}, error) {
 	var err error
 	var veClient *proxmox.VirtualEnvironmentClient
 
@@ -190,21 +535,186 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 			veConfig[mkProviderVirtualEnvironmentUsername].(string),
 			veConfig[mkProviderVirtualEnvironmentPassword].(string),
 			veConfig[mkProviderVirtualEnvironmentOTP].(string),
+			veConfig[mkProviderVirtualEnvironmentAPIToken].(string),
 			veConfig[mkProviderVirtualEnvironmentInsecure].(bool),
 		)
 
 		if err != nil {
 			return nil, err
 		}
+
+		endpoints := veConfig[mkProviderVirtualEnvironmentEndpoints].([]interface{})
+
+		for _, e := range endpoints {
+			err = veClient.AddEndpoints(e.(string))
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		caFile := veConfig[mkProviderVirtualEnvironmentCAFile].(string)
+		caPEM := veConfig[mkProviderVirtualEnvironmentCAPEM].(string)
+
+		if caFile != "" && caPEM != "" {
+			return nil, fmt.Errorf("The arguments \"%s\" and \"%s\" are mutually exclusive", mkProviderVirtualEnvironmentCAFile, mkProviderVirtualEnvironmentCAPEM)
+		}
+
+		if caFile != "" {
+			caFileContents, err := ioutil.ReadFile(caFile)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read the CA certificates from \"%s\" - Reason: %s", caFile, err.Error())
+			}
+
+			caPEM = string(caFileContents)
+		}
+
+		if caPEM != "" {
+			err = veClient.SetCACertificates([]byte(caPEM))
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tlsFingerprints := []string{}
+
+		for _, f := range veConfig[mkProviderVirtualEnvironmentTLSFingerprints].([]interface{}) {
+			tlsFingerprints = append(tlsFingerprints, f.(string))
+		}
+
+		if len(tlsFingerprints) == 0 {
+			for _, k := range []string{"PROXMOX_VE_TLS_FINGERPRINTS", "PM_VE_TLS_FINGERPRINTS"} {
+				if v := os.Getenv(k); v != "" {
+					tlsFingerprints = strings.Split(v, ",")
+
+					break
+				}
+			}
+		}
+
+		if len(tlsFingerprints) > 0 {
+			err = veClient.SetTLSFingerprints(tlsFingerprints)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		retryDelayMax, err := time.ParseDuration(veConfig[mkProviderVirtualEnvironmentRetryDelayMax].(string))
+
+		if err != nil {
+			return nil, err
+		}
+
+		retryDelayMin, err := time.ParseDuration(veConfig[mkProviderVirtualEnvironmentRetryDelayMin].(string))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if retryDelayMin > retryDelayMax {
+			return nil, fmt.Errorf("The value of \"%s\" must not exceed the value of \"%s\"", mkProviderVirtualEnvironmentRetryDelayMin, mkProviderVirtualEnvironmentRetryDelayMax)
+		}
+
+		veClient.RetryPolicy = &proxmox.VirtualEnvironmentRetryPolicy{
+			MaxDelay:   retryDelayMax,
+			MaxRetries: veConfig[mkProviderVirtualEnvironmentMaxRetries].(int),
+			MinDelay:   retryDelayMin,
+		}
+
+		sshConfigBlock := veConfig[mkProviderVirtualEnvironmentSSH].([]interface{})
+
+		if len(sshConfigBlock) > 0 && sshConfigBlock[0] != nil {
+			sshConfig := sshConfigBlock[0].(map[string]interface{})
+
+			hostKeyFingerprints := map[string]string{}
+
+			for nodeName, fingerprint := range sshConfig[mkProviderVirtualEnvironmentSSHHostKeys].(map[string]interface{}) {
+				hostKeyFingerprints[nodeName] = fingerprint.(string)
+			}
+
+			nodes := map[string]*proxmox.VirtualEnvironmentSSHNode{}
+
+			for _, n := range sshConfig[mkProviderVirtualEnvironmentSSHNode].([]interface{}) {
+				node := n.(map[string]interface{})
+				nodeName := node[mkProviderVirtualEnvironmentSSHNodeName].(string)
+
+				if _, ok := nodes[nodeName]; ok {
+					return nil, fmt.Errorf("The SSH address of node \"%s\" has been specified more than once", nodeName)
+				}
+
+				nodes[nodeName] = &proxmox.VirtualEnvironmentSSHNode{
+					Address: node[mkProviderVirtualEnvironmentSSHNodeAddress].(string),
+					Port:    node[mkProviderVirtualEnvironmentSSHNodePort].(int),
+				}
+			}
+
+			if sshConfig[mkProviderVirtualEnvironmentSSHTOFU].(bool) && sshConfig[mkProviderVirtualEnvironmentSSHKnownHostsFile].(string) == "" {
+				return nil, fmt.Errorf("The argument \"%s\" requires the argument \"%s\"", mkProviderVirtualEnvironmentSSHTOFU, mkProviderVirtualEnvironmentSSHKnownHostsFile)
+			}
+
+			veClient.SSH = &proxmox.VirtualEnvironmentSSHConfiguration{
+				Agent:                sshConfig[mkProviderVirtualEnvironmentSSHAgent].(bool),
+				AgentSocket:          sshConfig[mkProviderVirtualEnvironmentSSHAgentSocket].(string),
+				HostKeyFingerprints:  hostKeyFingerprints,
+				KnownHostsFile:       sshConfig[mkProviderVirtualEnvironmentSSHKnownHostsFile].(string),
+				Nodes:                nodes,
+				Password:             sshConfig[mkProviderVirtualEnvironmentSSHPassword].(string),
+				PrivateKey:           sshConfig[mkProviderVirtualEnvironmentSSHPrivateKey].(string),
+				PrivateKeyPassphrase: sshConfig[mkProviderVirtualEnvironmentSSHPrivateKeyPass].(string),
+				TrustOnFirstUse:      sshConfig[mkProviderVirtualEnvironmentSSHTOFU].(bool),
+				Username:             sshConfig[mkProviderVirtualEnvironmentSSHUsername].(string),
+			}
+
+			jumpHostBlock := sshConfig[mkProviderVirtualEnvironmentSSHJumpHost].([]interface{})
+
+			if len(jumpHostBlock) > 0 && jumpHostBlock[0] != nil {
+				jumpHost := jumpHostBlock[0].(map[string]interface{})
+
+				veClient.SSH.JumpHost = &proxmox.VirtualEnvironmentSSHJumpHost{
+					Address:              jumpHost[mkProviderVirtualEnvironmentSSHJumpHostAddr].(string),
+					HostKeyFingerprint:   jumpHost[mkProviderVirtualEnvironmentSSHJumpHostKey].(string),
+					Password:             jumpHost[mkProviderVirtualEnvironmentSSHPassword].(string),
+					PrivateKey:           jumpHost[mkProviderVirtualEnvironmentSSHPrivateKey].(string),
+					PrivateKeyPassphrase: jumpHost[mkProviderVirtualEnvironmentSSHPrivateKeyPass].(string),
+					Username:             jumpHost[mkProviderVirtualEnvironmentSSHUsername].(string),
+				}
+			}
+		}
+
+		if veConfig[mkProviderVirtualEnvironmentDiscoverEndpoints].(bool) {
+			err = veClient.DiscoverEndpoints(stopContext)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to discover the endpoints for the Proxmox Virtual Environment API - Reason: %s", err.Error())
+			}
+		}
 	}
 
 	config := providerConfiguration{
-		veClient: veClient,
+		stopContext: stopContext,
+		veClient:    veClient,
 	}
 
 	return config, nil
 }
 
+// GetStopContext returns a context, which is cancelled when Terraform asks the provider to stop (e.g. on interrupt).
+func (c *providerConfiguration) GetStopContext() context.Context {
+	if c.stopContext == nil {
+		return context.Background()
+	}
+
+	return c.stopContext
+}
+
+// GetTimeoutContext returns a context, which is cancelled when Terraform asks the provider to stop or when the resource timeout expires.
+func (c *providerConfiguration) GetTimeoutContext(d *schema.ResourceData, key string) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.GetStopContext(), d.Timeout(key))
+}
+
 func (c *providerConfiguration) GetVEClient() (*proxmox.VirtualEnvironmentClient, error) {
 	if c.veClient == nil {
 		return nil, errors.New("You must specify the virtual environment details in the provider configuration")
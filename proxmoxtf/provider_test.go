@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 // TestProviderInstantiation() tests whether the Provider instance can be instantiated.
@@ -36,18 +37,126 @@ func TestProviderSchema(t *testing.T) {
 	veSchema := testNestedSchemaExistence(t, s, mkProviderVirtualEnvironment)
 
 	testOptionalArguments(t, veSchema, []string{
+		mkProviderVirtualEnvironmentAPIToken,
+		mkProviderVirtualEnvironmentCAFile,
+		mkProviderVirtualEnvironmentCAPEM,
+		mkProviderVirtualEnvironmentDiscoverEndpoints,
 		mkProviderVirtualEnvironmentEndpoint,
+		mkProviderVirtualEnvironmentEndpoints,
 		mkProviderVirtualEnvironmentInsecure,
+		mkProviderVirtualEnvironmentMaxRetries,
 		mkProviderVirtualEnvironmentOTP,
 		mkProviderVirtualEnvironmentPassword,
+		mkProviderVirtualEnvironmentRetryDelayMax,
+		mkProviderVirtualEnvironmentRetryDelayMin,
+		mkProviderVirtualEnvironmentSSH,
+		mkProviderVirtualEnvironmentTLSFingerprints,
 		mkProviderVirtualEnvironmentUsername,
 	})
 
 	testValueTypes(t, veSchema, map[string]schema.ValueType{
-		mkProviderVirtualEnvironmentEndpoint: schema.TypeString,
-		mkProviderVirtualEnvironmentInsecure: schema.TypeBool,
-		mkProviderVirtualEnvironmentOTP:      schema.TypeString,
-		mkProviderVirtualEnvironmentPassword: schema.TypeString,
-		mkProviderVirtualEnvironmentUsername: schema.TypeString,
+		mkProviderVirtualEnvironmentAPIToken:          schema.TypeString,
+		mkProviderVirtualEnvironmentCAFile:            schema.TypeString,
+		mkProviderVirtualEnvironmentCAPEM:             schema.TypeString,
+		mkProviderVirtualEnvironmentDiscoverEndpoints: schema.TypeBool,
+		mkProviderVirtualEnvironmentEndpoint:          schema.TypeString,
+		mkProviderVirtualEnvironmentEndpoints:         schema.TypeList,
+		mkProviderVirtualEnvironmentInsecure:          schema.TypeBool,
+		mkProviderVirtualEnvironmentMaxRetries:        schema.TypeInt,
+		mkProviderVirtualEnvironmentOTP:               schema.TypeString,
+		mkProviderVirtualEnvironmentPassword:          schema.TypeString,
+		mkProviderVirtualEnvironmentRetryDelayMax:     schema.TypeString,
+		mkProviderVirtualEnvironmentRetryDelayMin:     schema.TypeString,
+		mkProviderVirtualEnvironmentSSH:               schema.TypeList,
+		mkProviderVirtualEnvironmentTLSFingerprints:   schema.TypeList,
+		mkProviderVirtualEnvironmentUsername:          schema.TypeString,
 	})
+
+	sshSchema := testNestedSchemaExistence(t, veSchema, mkProviderVirtualEnvironmentSSH)
+
+	testOptionalArguments(t, sshSchema, []string{
+		mkProviderVirtualEnvironmentSSHAgent,
+		mkProviderVirtualEnvironmentSSHAgentSocket,
+		mkProviderVirtualEnvironmentSSHHostKeys,
+		mkProviderVirtualEnvironmentSSHJumpHost,
+		mkProviderVirtualEnvironmentSSHKnownHostsFile,
+		mkProviderVirtualEnvironmentSSHNode,
+		mkProviderVirtualEnvironmentSSHPassword,
+		mkProviderVirtualEnvironmentSSHPrivateKey,
+		mkProviderVirtualEnvironmentSSHPrivateKeyPass,
+		mkProviderVirtualEnvironmentSSHTOFU,
+		mkProviderVirtualEnvironmentSSHUsername,
+	})
+
+	testValueTypes(t, sshSchema, map[string]schema.ValueType{
+		mkProviderVirtualEnvironmentSSHAgent:          schema.TypeBool,
+		mkProviderVirtualEnvironmentSSHAgentSocket:    schema.TypeString,
+		mkProviderVirtualEnvironmentSSHHostKeys:       schema.TypeMap,
+		mkProviderVirtualEnvironmentSSHJumpHost:       schema.TypeList,
+		mkProviderVirtualEnvironmentSSHKnownHostsFile: schema.TypeString,
+		mkProviderVirtualEnvironmentSSHNode:           schema.TypeList,
+		mkProviderVirtualEnvironmentSSHPassword:       schema.TypeString,
+		mkProviderVirtualEnvironmentSSHPrivateKey:     schema.TypeString,
+		mkProviderVirtualEnvironmentSSHPrivateKeyPass: schema.TypeString,
+		mkProviderVirtualEnvironmentSSHTOFU:           schema.TypeBool,
+		mkProviderVirtualEnvironmentSSHUsername:       schema.TypeString,
+	})
+
+	jumpHostSchema := testNestedSchemaExistence(t, sshSchema, mkProviderVirtualEnvironmentSSHJumpHost)
+
+	testRequiredArguments(t, jumpHostSchema, []string{
+		mkProviderVirtualEnvironmentSSHJumpHostAddr,
+	})
+
+	testOptionalArguments(t, jumpHostSchema, []string{
+		mkProviderVirtualEnvironmentSSHJumpHostKey,
+		mkProviderVirtualEnvironmentSSHPassword,
+		mkProviderVirtualEnvironmentSSHPrivateKey,
+		mkProviderVirtualEnvironmentSSHPrivateKeyPass,
+		mkProviderVirtualEnvironmentSSHUsername,
+	})
+
+	testValueTypes(t, jumpHostSchema, map[string]schema.ValueType{
+		mkProviderVirtualEnvironmentSSHJumpHostAddr:   schema.TypeString,
+		mkProviderVirtualEnvironmentSSHJumpHostKey:    schema.TypeString,
+		mkProviderVirtualEnvironmentSSHPassword:       schema.TypeString,
+		mkProviderVirtualEnvironmentSSHPrivateKey:     schema.TypeString,
+		mkProviderVirtualEnvironmentSSHPrivateKeyPass: schema.TypeString,
+		mkProviderVirtualEnvironmentSSHUsername:       schema.TypeString,
+	})
+
+	nodeSchema := testNestedSchemaExistence(t, sshSchema, mkProviderVirtualEnvironmentSSHNode)
+
+	testRequiredArguments(t, nodeSchema, []string{
+		mkProviderVirtualEnvironmentSSHNodeAddress,
+		mkProviderVirtualEnvironmentSSHNodeName,
+	})
+
+	testOptionalArguments(t, nodeSchema, []string{
+		mkProviderVirtualEnvironmentSSHNodePort,
+	})
+
+	testValueTypes(t, nodeSchema, map[string]schema.ValueType{
+		mkProviderVirtualEnvironmentSSHNodeAddress: schema.TypeString,
+		mkProviderVirtualEnvironmentSSHNodeName:    schema.TypeString,
+		mkProviderVirtualEnvironmentSSHNodePort:    schema.TypeInt,
+	})
+}
+
+// TestProviderValidateAPIToken() tests whether the Provider accepts an API token without a username and password.
+func TestProviderValidateAPIToken(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		mkProviderVirtualEnvironment: []interface{}{
+			map[string]interface{}{
+				mkProviderVirtualEnvironmentAPIToken: "terraform@pve!provider=00000000-0000-0000-0000-000000000000",
+				mkProviderVirtualEnvironmentEndpoint: "https://pve.example.com:8006",
+			},
+		},
+	})
+
+	_, errs := Provider().Validate(config)
+
+	for _, err := range errs {
+		t.Errorf("Unexpected validation error: %s", err.Error())
+	}
 }
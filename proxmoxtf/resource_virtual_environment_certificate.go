@@ -14,6 +14,8 @@ import (
 )
 
 const (
+	pemEndCertificate = "-----END CERTIFICATE-----"
+
 	dvResourceVirtualEnvironmentCertificateCertificateChain = ""
 	dvResourceVirtualEnvironmentCertificateOverwrite        = false
 
@@ -116,6 +118,9 @@ func resourceVirtualEnvironmentCertificate() *schema.Resource {
 		Read:   resourceVirtualEnvironmentCertificateRead,
 		Update: resourceVirtualEnvironmentCertificateUpdate,
 		Delete: resourceVirtualEnvironmentCertificateDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVirtualEnvironmentCertificateImport,
+		},
 	}
 }
 
@@ -163,6 +168,15 @@ func resourceVirtualEnvironmentCertificateGetUpdateBody(d *schema.ResourceData,
 	return body, nil
 }
 
+func resourceVirtualEnvironmentCertificateImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	nodeName := d.Id()
+
+	d.SetId(fmt.Sprintf("%s_certificate", nodeName))
+	d.Set(mkResourceVirtualEnvironmentCertificateNodeName, nodeName)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceVirtualEnvironmentCertificateRead(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
@@ -175,26 +189,42 @@ func resourceVirtualEnvironmentCertificateRead(d *schema.ResourceData, m interfa
 	list, err := veClient.ListCertificates(nodeName)
 
 	if err != nil {
+		if proxmox.IsNotFound(err) {
+			d.SetId("")
+
+			return nil
+		}
+
 		return err
 	}
 
+	// The chain is only split from the certificate, if it has been specified or the state is empty due to an import.
+	splitChain := d.Get(mkResourceVirtualEnvironmentCertificateCertificateChain).(string) != "" ||
+		d.Get(mkResourceVirtualEnvironmentCertificateCertificate).(string) == ""
+
 	d.Set(mkResourceVirtualEnvironmentCertificateCertificate, "")
 	d.Set(mkResourceVirtualEnvironmentCertificateCertificateChain, "")
 
-	certificateChain := d.Get(mkResourceVirtualEnvironmentCertificateCertificateChain).(string)
-
 	for _, c := range *list {
 		if c.FileName != nil && *c.FileName == "pveproxy-ssl.pem" {
 			if c.Certificates != nil {
 				newCertificate := ""
 				newCertificateChain := ""
 
-				if certificateChain != "" {
-					certificates := strings.Split(strings.TrimSpace(*c.Certificates), "\n")
-					newCertificate = certificates[0] + "\n"
+				if splitChain {
+					certificates := strings.TrimSpace(*c.Certificates)
+					endIndex := strings.Index(certificates, pemEndCertificate)
+
+					if endIndex >= 0 {
+						endIndex += len(pemEndCertificate)
+						newCertificate = certificates[:endIndex] + "\n"
+						remainder := strings.TrimSpace(certificates[endIndex:])
 
-					if len(certificates) > 1 {
-						newCertificateChain = strings.Join(certificates[1:], "\n") + "\n"
+						if remainder != "" {
+							newCertificateChain = remainder + "\n"
+						}
+					} else {
+						newCertificate = *c.Certificates
 					}
 				} else {
 					newCertificate = *c.Certificates
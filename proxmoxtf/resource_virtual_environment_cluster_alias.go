@@ -5,8 +5,6 @@
 package proxmoxtf
 
 import (
-	"strings"
-
 	"github.com/danitso/terraform-provider-proxmox/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -45,6 +43,9 @@ func resourceVirtualEnvironmentClusterAlias() *schema.Resource {
 		Read:   resourceVirtualEnvironmentClusterAliasRead,
 		Update: resourceVirtualEnvironmentClusterAliasUpdate,
 		Delete: resourceVirtualEnvironmentClusterAliasDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -89,7 +90,7 @@ func resourceVirtualEnvironmentClusterAliasRead(d *schema.ResourceData, m interf
 	alias, err := veClient.GetAlias(name)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -156,7 +157,7 @@ func resourceVirtualEnvironmentClusterAliasDelete(d *schema.ResourceData, m inte
 	err = veClient.DeleteAlias(name)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
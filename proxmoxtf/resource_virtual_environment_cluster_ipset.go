@@ -5,8 +5,6 @@
 package proxmoxtf
 
 import (
-	"strings"
-
 	"github.com/danitso/terraform-provider-proxmox/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -75,6 +73,9 @@ func resourceVirtualEnvironmentClusterIPSet() *schema.Resource {
 		Read:   resourceVirtualEnvironmentClusterIPSetRead,
 		Update: resourceVirtualEnvironmentClusterIPSetUpdate,
 		Delete: resourceVirtualEnvironmentClusterIPSetDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -169,7 +170,7 @@ func resourceVirtualEnvironmentClusterIPSetRead(d *schema.ResourceData, m interf
 	IPSet, err := veClient.GetListIPSetContent(name)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -177,8 +178,27 @@ func resourceVirtualEnvironmentClusterIPSetRead(d *schema.ResourceData, m interf
 		return err
 	}
 
-	for key, _ := range IPSet {
-		d.Set(mkResourceVirtualEnvironmentClusterIPSetCIDR, IPSet[key])
+	cidrs := make([]interface{}, len(IPSet))
+
+	for i, v := range IPSet {
+		cidr := map[string]interface{}{}
+
+		cidr[mkResourceVirtualEnvironmentClusterIPSetCIDRName] = v.CIDR
+		cidr[mkResourceVirtualEnvironmentClusterIPSetCIDRComment] = v.Comment
+
+		if v.NoMatch != nil {
+			cidr[mkResourceVirtualEnvironmentClusterIPSetCIDRNoMatch] = bool(*v.NoMatch)
+		} else {
+			cidr[mkResourceVirtualEnvironmentClusterIPSetCIDRNoMatch] = false
+		}
+
+		cidrs[i] = cidr
+	}
+
+	err = d.Set(mkResourceVirtualEnvironmentClusterIPSetCIDR, cidrs)
+
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -242,7 +262,7 @@ func resourceVirtualEnvironmentClusterIPSetDelete(d *schema.ResourceData, m inte
 	err = veClient.DeleteIPSet(name)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -5,9 +5,11 @@
 package proxmoxtf
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/danitso/terraform-provider-proxmox/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -228,6 +230,7 @@ func resourceVirtualEnvironmentContainer() *schema.Resource {
 				Type:        schema.TypeList,
 				Description: "The disks",
 				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
 				DefaultFunc: func() (interface{}, error) {
 					return []interface{}{
@@ -494,14 +497,19 @@ func resourceVirtualEnvironmentContainer() *schema.Resource {
 				Type:        schema.TypeList,
 				Description: "The operating system configuration",
 				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						mkResourceVirtualEnvironmentContainerOperatingSystemTemplateFileID: {
-							Type:         schema.TypeString,
-							Description:  "The ID of an OS template file",
-							Required:     true,
-							ForceNew:     true,
+							Type:        schema.TypeString,
+							Description: "The ID of an OS template file",
+							Required:    true,
+							ForceNew:    true,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								// The template is not available through the API, which is why imported containers have no value.
+								return old == "" && d.Id() != ""
+							},
 							ValidateFunc: getFileIDValidator(),
 						},
 						mkResourceVirtualEnvironmentContainerOperatingSystemType: {
@@ -552,20 +560,32 @@ func resourceVirtualEnvironmentContainer() *schema.Resource {
 		Read:   resourceVirtualEnvironmentContainerRead,
 		Update: resourceVirtualEnvironmentContainerUpdate,
 		Delete: resourceVirtualEnvironmentContainerDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVirtualEnvironmentContainerImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 	}
 }
 
 func resourceVirtualEnvironmentContainerCreate(d *schema.ResourceData, m interface{}) error {
+	config := m.(providerConfiguration)
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
+
 	clone := d.Get(mkResourceVirtualEnvironmentContainerClone).([]interface{})
 
 	if len(clone) > 0 {
-		return resourceVirtualEnvironmentContainerCreateClone(d, m)
+		return resourceVirtualEnvironmentContainerCreateClone(ctx, d, m)
 	}
 
-	return resourceVirtualEnvironmentContainerCreateCustom(d, m)
+	return resourceVirtualEnvironmentContainerCreateCustom(ctx, d, m)
 }
 
-func resourceVirtualEnvironmentContainerCreateClone(d *schema.ResourceData, m interface{}) error {
+func resourceVirtualEnvironmentContainerCreateClone(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
 
@@ -626,12 +646,14 @@ func resourceVirtualEnvironmentContainerCreateClone(d *schema.ResourceData, m in
 		cloneBody.PoolID = &poolID
 	}
 
+	var task *proxmox.VirtualEnvironmentTask
+
 	if cloneNodeName != "" && cloneNodeName != nodeName {
 		cloneBody.TargetNodeName = &nodeName
 
-		err = veClient.CloneContainer(cloneNodeName, cloneVMID, cloneBody)
+		task, err = veClient.CloneContainerAsync(ctx, cloneNodeName, cloneVMID, cloneBody)
 	} else {
-		err = veClient.CloneContainer(nodeName, cloneVMID, cloneBody)
+		task, err = veClient.CloneContainerAsync(ctx, nodeName, cloneVMID, cloneBody)
 	}
 
 	if err != nil {
@@ -640,8 +662,15 @@ func resourceVirtualEnvironmentContainerCreateClone(d *schema.ResourceData, m in
 
 	d.SetId(strconv.Itoa(vmID))
 
-	// Wait for the container to be created and its configuration lock to be released.
-	err = veClient.WaitForContainerLock(nodeName, vmID, 600, 5, true)
+	// Wait for the clone task to complete.
+	err = task.WaitContext(ctx, 600, 5)
+
+	if err != nil {
+		return err
+	}
+
+	// Wait for the container's configuration lock to be released.
+	err = veClient.WaitForContainerLockContext(ctx, nodeName, vmID, 600, 5, true)
 
 	if err != nil {
 		return err
@@ -866,16 +895,16 @@ func resourceVirtualEnvironmentContainerCreateClone(d *schema.ResourceData, m in
 	}
 
 	// Wait for the container's lock to be released.
-	err = veClient.WaitForContainerLock(nodeName, vmID, 600, 5, true)
+	err = veClient.WaitForContainerLockContext(ctx, nodeName, vmID, 600, 5, true)
 
 	if err != nil {
 		return err
 	}
 
-	return resourceVirtualEnvironmentContainerCreateStart(d, m)
+	return resourceVirtualEnvironmentContainerCreateStart(ctx, d, m)
 }
 
-func resourceVirtualEnvironmentContainerCreateCustom(d *schema.ResourceData, m interface{}) error {
+func resourceVirtualEnvironmentContainerCreateCustom(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
 
@@ -1119,7 +1148,7 @@ func resourceVirtualEnvironmentContainerCreateCustom(d *schema.ResourceData, m i
 		createBody.PoolID = &poolID
 	}
 
-	err = veClient.CreateContainer(nodeName, &createBody)
+	task, err := veClient.CreateContainerAsync(ctx, nodeName, &createBody)
 
 	if err != nil {
 		return err
@@ -1127,17 +1156,24 @@ func resourceVirtualEnvironmentContainerCreateCustom(d *schema.ResourceData, m i
 
 	d.SetId(strconv.Itoa(vmID))
 
+	// Wait for the create task to complete.
+	err = task.WaitContext(ctx, 600, 5)
+
+	if err != nil {
+		return err
+	}
+
 	// Wait for the container's lock to be released.
-	err = veClient.WaitForContainerLock(nodeName, vmID, 600, 5, true)
+	err = veClient.WaitForContainerLockContext(ctx, nodeName, vmID, 600, 5, true)
 
 	if err != nil {
 		return err
 	}
 
-	return resourceVirtualEnvironmentContainerCreateStart(d, m)
+	return resourceVirtualEnvironmentContainerCreateStart(ctx, d, m)
 }
 
-func resourceVirtualEnvironmentContainerCreateStart(d *schema.ResourceData, m interface{}) error {
+func resourceVirtualEnvironmentContainerCreateStart(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	started := d.Get(mkResourceVirtualEnvironmentContainerStarted).(bool)
 	template := d.Get(mkResourceVirtualEnvironmentContainerTemplate).(bool)
 
@@ -1160,13 +1196,13 @@ func resourceVirtualEnvironmentContainerCreateStart(d *schema.ResourceData, m in
 	}
 
 	// Start the container and wait for it to reach a running state before continuing.
-	err = veClient.StartContainer(nodeName, vmID)
+	task, err := veClient.StartContainerAsync(ctx, nodeName, vmID)
 
 	if err != nil {
 		return err
 	}
 
-	err = veClient.WaitForContainerState(nodeName, vmID, "running", 120, 5)
+	err = task.WaitContext(ctx, 120, 5)
 
 	if err != nil {
 		return err
@@ -1206,6 +1242,38 @@ func resourceVirtualEnvironmentContainerGetOperatingSystemTypeValidator() schema
 	}, false)
 }
 
+func resourceVirtualEnvironmentContainerImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodeName, vmID, err := parseNodeResourceID(d.Id())
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = veClient.GetContainer(nodeName, vmID)
+
+	if err != nil {
+		if proxmox.IsNotFound(err) {
+			return nil, fmt.Errorf("Container \"%d\" does not exist on node \"%s\"", vmID, nodeName)
+		}
+
+		return nil, err
+	}
+
+	d.SetId(strconv.Itoa(vmID))
+
+	d.Set(mkResourceVirtualEnvironmentContainerNodeName, nodeName)
+	d.Set(mkResourceVirtualEnvironmentContainerVMID, vmID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceVirtualEnvironmentContainerRead(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
@@ -1225,8 +1293,7 @@ func resourceVirtualEnvironmentContainerRead(d *schema.ResourceData, m interface
 	containerConfig, err := veClient.GetContainer(nodeName, vmID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") ||
-			(strings.Contains(err.Error(), "HTTP 500") && strings.Contains(err.Error(), "does not exist")) {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -1338,11 +1405,10 @@ func resourceVirtualEnvironmentContainerRead(d *schema.ResourceData, m interface
 
 	if len(clone) > 0 {
 		if len(currentDisk) > 0 {
-			d.Set(mkResourceVirtualEnvironmentContainerDiskDatastoreID, []interface{}{disk})
+			d.Set(mkResourceVirtualEnvironmentContainerDisk, []interface{}{disk})
 		}
-	} else if len(currentDisk) > 0 ||
-		disk[mkResourceVirtualEnvironmentContainerDiskDatastoreID] != dvResourceVirtualEnvironmentContainerDiskDatastoreID {
-		d.Set(mkResourceVirtualEnvironmentContainerDiskDatastoreID, []interface{}{disk})
+	} else {
+		d.Set(mkResourceVirtualEnvironmentContainerDisk, []interface{}{disk})
 	}
 
 	// Compare the memory configuration to the one stored in the state.
@@ -1565,14 +1631,15 @@ func resourceVirtualEnvironmentContainerRead(d *schema.ResourceData, m interface
 		currentOperatingSystemMap := currentOperatingSystem[0].(map[string]interface{})
 
 		operatingSystem[mkResourceVirtualEnvironmentContainerOperatingSystemTemplateFileID] = currentOperatingSystemMap[mkResourceVirtualEnvironmentContainerOperatingSystemTemplateFileID]
+	} else {
+		operatingSystem[mkResourceVirtualEnvironmentContainerOperatingSystemTemplateFileID] = ""
 	}
 
 	if len(clone) > 0 {
-		if len(currentMemory) > 0 {
+		if len(currentOperatingSystem) > 0 {
 			d.Set(mkResourceVirtualEnvironmentContainerOperatingSystem, []interface{}{operatingSystem})
 		}
-	} else if len(currentOperatingSystem) > 0 ||
-		operatingSystem[mkResourceVirtualEnvironmentContainerOperatingSystemType] != dvResourceVirtualEnvironmentContainerOperatingSystemType {
+	} else {
 		d.Set(mkResourceVirtualEnvironmentContainerOperatingSystem, []interface{}{operatingSystem})
 	}
 
@@ -1600,6 +1667,8 @@ func resourceVirtualEnvironmentContainerRead(d *schema.ResourceData, m interface
 
 func resourceVirtualEnvironmentContainerUpdate(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 	veClient, err := config.GetVEClient()
 
 	if err != nil {
@@ -1844,13 +1913,13 @@ func resourceVirtualEnvironmentContainerUpdate(d *schema.ResourceData, m interfa
 
 	if d.HasChange(mkResourceVirtualEnvironmentContainerStarted) && !bool(template) {
 		if started {
-			err = veClient.StartContainer(nodeName, vmID)
+			task, err := veClient.StartContainerAsync(ctx, nodeName, vmID)
 
 			if err != nil {
 				return err
 			}
 
-			err = veClient.WaitForContainerState(nodeName, vmID, "running", 300, 5)
+			err = task.WaitContext(ctx, 300, 5)
 
 			if err != nil {
 				return err
@@ -1859,7 +1928,7 @@ func resourceVirtualEnvironmentContainerUpdate(d *schema.ResourceData, m interfa
 			forceStop := proxmox.CustomBool(true)
 			shutdownTimeout := 300
 
-			err = veClient.ShutdownContainer(nodeName, vmID, &proxmox.VirtualEnvironmentContainerShutdownRequestBody{
+			task, err := veClient.ShutdownContainerAsync(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentContainerShutdownRequestBody{
 				ForceStop: &forceStop,
 				Timeout:   &shutdownTimeout,
 			})
@@ -1868,7 +1937,7 @@ func resourceVirtualEnvironmentContainerUpdate(d *schema.ResourceData, m interfa
 				return err
 			}
 
-			err = veClient.WaitForContainerState(nodeName, vmID, "stopped", 300, 5)
+			err = task.WaitContext(ctx, shutdownTimeout+30, 5)
 
 			if err != nil {
 				return err
@@ -1882,13 +1951,19 @@ func resourceVirtualEnvironmentContainerUpdate(d *schema.ResourceData, m interfa
 	if !bool(template) && rebootRequired {
 		rebootTimeout := 300
 
-		err = veClient.RebootContainer(nodeName, vmID, &proxmox.VirtualEnvironmentContainerRebootRequestBody{
+		task, err := veClient.RebootContainerAsync(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentContainerRebootRequestBody{
 			Timeout: &rebootTimeout,
 		})
 
 		if err != nil {
 			return err
 		}
+
+		err = task.WaitContext(ctx, rebootTimeout+30, 5)
+
+		if err != nil {
+			return err
+		}
 	}
 
 	return resourceVirtualEnvironmentContainerRead(d, m)
@@ -1896,6 +1971,8 @@ func resourceVirtualEnvironmentContainerUpdate(d *schema.ResourceData, m interfa
 
 func resourceVirtualEnvironmentContainerDelete(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 	veClient, err := config.GetVEClient()
 
 	if err != nil {
@@ -1920,7 +1997,7 @@ func resourceVirtualEnvironmentContainerDelete(d *schema.ResourceData, m interfa
 		forceStop := proxmox.CustomBool(true)
 		shutdownTimeout := 300
 
-		err = veClient.ShutdownContainer(nodeName, vmID, &proxmox.VirtualEnvironmentContainerShutdownRequestBody{
+		task, err := veClient.ShutdownContainerAsync(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentContainerShutdownRequestBody{
 			ForceStop: &forceStop,
 			Timeout:   &shutdownTimeout,
 		})
@@ -1929,17 +2006,17 @@ func resourceVirtualEnvironmentContainerDelete(d *schema.ResourceData, m interfa
 			return err
 		}
 
-		err = veClient.WaitForContainerState(nodeName, vmID, "stopped", 30, 5)
+		err = task.WaitContext(ctx, shutdownTimeout+30, 5)
 
 		if err != nil {
 			return err
 		}
 	}
 
-	err = veClient.DeleteContainer(nodeName, vmID)
+	task, err := veClient.DeleteContainerAsync(ctx, nodeName, vmID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -1948,11 +2025,11 @@ func resourceVirtualEnvironmentContainerDelete(d *schema.ResourceData, m interfa
 		return err
 	}
 
-	// Wait for the state to become unavailable as that clearly indicates the destruction of the container.
-	err = veClient.WaitForContainerState(nodeName, vmID, "", 60, 2)
+	// Wait for the destroy task to complete as that clearly indicates the destruction of the container.
+	err = task.WaitContext(ctx, 60, 2)
 
-	if err == nil {
-		return fmt.Errorf("Failed to delete container \"%d\"", vmID)
+	if err != nil {
+		return err
 	}
 
 	d.SetId("")
@@ -47,6 +47,9 @@ func resourceVirtualEnvironmentDNS() *schema.Resource {
 		Read:   resourceVirtualEnvironmentDNSRead,
 		Update: resourceVirtualEnvironmentDNSUpdate,
 		Delete: resourceVirtualEnvironmentDNSDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVirtualEnvironmentDNSImport,
+		},
 	}
 }
 
@@ -88,6 +91,15 @@ func resourceVirtualEnvironmentDNSGetUpdateBody(d *schema.ResourceData, m interf
 	return body, nil
 }
 
+func resourceVirtualEnvironmentDNSImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	nodeName := d.Id()
+
+	d.SetId(fmt.Sprintf("%s_dns", nodeName))
+	d.Set(mkResourceVirtualEnvironmentDNSNodeName, nodeName)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceVirtualEnvironmentDNSRead(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
@@ -100,6 +112,12 @@ func resourceVirtualEnvironmentDNSRead(d *schema.ResourceData, m interface{}) er
 	dns, err := veClient.GetDNS(nodeName)
 
 	if err != nil {
+		if proxmox.IsNotFound(err) {
+			d.SetId("")
+
+			return nil
+		}
+
 		return err
 	}
 
@@ -55,11 +55,14 @@ func resourceVirtualEnvironmentFile() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			mkResourceVirtualEnvironmentFileContentType: {
-				Type:         schema.TypeString,
-				Description:  "The content type",
-				Optional:     true,
-				ForceNew:     true,
-				Default:      dvResourceVirtualEnvironmentFileContentType,
+				Type:        schema.TypeString,
+				Description: "The content type",
+				Optional:    true,
+				ForceNew:    true,
+				Default:     dvResourceVirtualEnvironmentFileContentType,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return new == "" && resourceVirtualEnvironmentFileIsImported(d)
+				},
 				ValidateFunc: getContentTypeValidator(),
 			},
 			mkResourceVirtualEnvironmentFileDatastoreID: {
@@ -105,6 +108,9 @@ func resourceVirtualEnvironmentFile() *schema.Resource {
 				DefaultFunc: func() (interface{}, error) {
 					return make([]interface{}, 1), nil
 				},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return resourceVirtualEnvironmentFileIsImported(d)
+				},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						mkResourceVirtualEnvironmentFileSourceFilePath: {
@@ -154,6 +160,9 @@ func resourceVirtualEnvironmentFile() *schema.Resource {
 				DefaultFunc: func() (interface{}, error) {
 					return make([]interface{}, 1), nil
 				},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return resourceVirtualEnvironmentFileIsImported(d)
+				},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						mkResourceVirtualEnvironmentFileSourceRawData: {
@@ -184,6 +193,9 @@ func resourceVirtualEnvironmentFile() *schema.Resource {
 		Create: resourceVirtualEnvironmentFileCreate,
 		Read:   resourceVirtualEnvironmentFileRead,
 		Delete: resourceVirtualEnvironmentFileDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVirtualEnvironmentFileImport,
+		},
 	}
 }
 
@@ -498,6 +510,48 @@ func resourceVirtualEnvironmentFileGetVolumeID(d *schema.ResourceData, m interfa
 	return &volumeID, nil
 }
 
+func resourceVirtualEnvironmentFileImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	idParts := strings.SplitN(d.Id(), "/", 2)
+
+	if len(idParts) != 2 || idParts[0] == "" {
+		return nil, fmt.Errorf("Invalid import identifier \"%s\" (valid: node_name/datastore_id:content_type/file_name)", d.Id())
+	}
+
+	nodeName := idParts[0]
+	volumeID := idParts[1]
+	volumeParts := strings.SplitN(volumeID, ":", 2)
+
+	if len(volumeParts) != 2 || volumeParts[0] == "" {
+		return nil, fmt.Errorf("Invalid import identifier \"%s\" (valid: node_name/datastore_id:content_type/file_name)", d.Id())
+	}
+
+	contentParts := strings.SplitN(volumeParts[1], "/", 2)
+
+	if len(contentParts) != 2 || contentParts[0] == "" || contentParts[1] == "" {
+		return nil, fmt.Errorf("Invalid import identifier \"%s\" (valid: node_name/datastore_id:content_type/file_name)", d.Id())
+	}
+
+	d.SetId(volumeID)
+
+	d.Set(mkResourceVirtualEnvironmentFileContentType, contentParts[0])
+	d.Set(mkResourceVirtualEnvironmentFileDatastoreID, volumeParts[0])
+	d.Set(mkResourceVirtualEnvironmentFileNodeName, nodeName)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceVirtualEnvironmentFileIsImported determines whether the file has been imported, in which case its source is unknown.
+func resourceVirtualEnvironmentFileIsImported(d *schema.ResourceData) bool {
+	if d.Id() == "" {
+		return false
+	}
+
+	sourceFile, _ := d.GetChange(mkResourceVirtualEnvironmentFileSourceFile)
+	sourceRaw, _ := d.GetChange(mkResourceVirtualEnvironmentFileSourceRaw)
+
+	return len(sourceFile.([]interface{})) == 0 && len(sourceRaw.([]interface{})) == 0
+}
+
 func resourceVirtualEnvironmentFileIsURL(d *schema.ResourceData, m interface{}) bool {
 	sourceFile := d.Get(mkResourceVirtualEnvironmentFileSourceFile).([]interface{})
 	sourceFilePath := ""
@@ -525,19 +579,38 @@ func resourceVirtualEnvironmentFileRead(d *schema.ResourceData, m interface{}) e
 	sourceFile := d.Get(mkResourceVirtualEnvironmentFileSourceFile).([]interface{})
 	sourceFilePath := ""
 
+	list, err := veClient.ListDatastoreFiles(nodeName, datastoreID)
+
+	if err != nil {
+		if proxmox.IsNotFound(err) {
+			d.SetId("")
+
+			return nil
+		}
+
+		return err
+	}
+
 	if len(sourceFile) == 0 {
+		// Files without a local source are only checked for existence, as there is nothing to compare them to.
+		for _, v := range list {
+			if v.VolumeID == d.Id() {
+				volumeParts := strings.SplitN(v.VolumeID, "/", 2)
+
+				d.Set(mkResourceVirtualEnvironmentFileFileName, volumeParts[len(volumeParts)-1])
+
+				return nil
+			}
+		}
+
+		d.SetId("")
+
 		return nil
 	}
 
 	sourceFileBlock := sourceFile[0].(map[string]interface{})
 	sourceFilePath = sourceFileBlock[mkResourceVirtualEnvironmentFileSourceFilePath].(string)
 
-	list, err := veClient.ListDatastoreFiles(nodeName, datastoreID)
-
-	if err != nil {
-		return err
-	}
-
 	fileIsURL := resourceVirtualEnvironmentFileIsURL(d, m)
 	fileName, err := resourceVirtualEnvironmentFileGetFileName(d, m)
 
@@ -645,7 +718,7 @@ func resourceVirtualEnvironmentFileDelete(d *schema.ResourceData, m interface{})
 	err = veClient.DeleteDatastoreFile(nodeName, datastoreID, d.Id())
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -5,8 +5,6 @@
 package proxmoxtf
 
 import (
-	"strings"
-
 	"github.com/danitso/terraform-provider-proxmox/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -77,6 +75,9 @@ func resourceVirtualEnvironmentGroup() *schema.Resource {
 		Read:   resourceVirtualEnvironmentGroupRead,
 		Update: resourceVirtualEnvironmentGroupUpdate,
 		Delete: resourceVirtualEnvironmentGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -141,7 +142,7 @@ func resourceVirtualEnvironmentGroupRead(d *schema.ResourceData, m interface{})
 	group, err := veClient.GetGroup(groupID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -184,6 +185,7 @@ func resourceVirtualEnvironmentGroupRead(d *schema.ResourceData, m interface{})
 		d.Set(mkResourceVirtualEnvironmentGroupComment, "")
 	}
 
+	d.Set(mkResourceVirtualEnvironmentGroupID, groupID)
 	d.Set(mkResourceVirtualEnvironmentGroupMembers, group.Members)
 
 	return nil
@@ -292,7 +294,7 @@ func resourceVirtualEnvironmentGroupDelete(d *schema.ResourceData, m interface{}
 	err = veClient.DeleteGroup(groupID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -100,6 +100,9 @@ func resourceVirtualEnvironmentHosts() *schema.Resource {
 		Read:   resourceVirtualEnvironmentHostsRead,
 		Update: resourceVirtualEnvironmentHostsUpdate,
 		Delete: resourceVirtualEnvironmentHostsDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVirtualEnvironmentHostsImport,
+		},
 	}
 }
 
@@ -117,6 +120,15 @@ func resourceVirtualEnvironmentHostsCreate(d *schema.ResourceData, m interface{}
 	return nil
 }
 
+func resourceVirtualEnvironmentHostsImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	nodeName := d.Id()
+
+	d.SetId(fmt.Sprintf("%s_hosts", nodeName))
+	d.Set(mkResourceVirtualEnvironmentHostsNodeName, nodeName)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceVirtualEnvironmentHostsRead(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
@@ -129,6 +141,12 @@ func resourceVirtualEnvironmentHostsRead(d *schema.ResourceData, m interface{})
 	hosts, err := veClient.GetHosts(nodeName)
 
 	if err != nil {
+		if proxmox.IsNotFound(err) {
+			d.SetId("")
+
+			return nil
+		}
+
 		return err
 	}
 
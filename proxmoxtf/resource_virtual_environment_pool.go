@@ -5,8 +5,6 @@
 package proxmoxtf
 
 import (
-	"strings"
-
 	"github.com/danitso/terraform-provider-proxmox/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -78,6 +76,9 @@ func resourceVirtualEnvironmentPool() *schema.Resource {
 		Read:   resourceVirtualEnvironmentPoolRead,
 		Update: resourceVirtualEnvironmentPoolUpdate,
 		Delete: resourceVirtualEnvironmentPoolDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -120,7 +121,7 @@ func resourceVirtualEnvironmentPoolRead(d *schema.ResourceData, m interface{}) e
 	pool, err := veClient.GetPool(poolID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -161,6 +162,7 @@ func resourceVirtualEnvironmentPoolRead(d *schema.ResourceData, m interface{}) e
 	}
 
 	d.Set(mkResourceVirtualEnvironmentPoolMembers, members)
+	d.Set(mkResourceVirtualEnvironmentPoolPoolID, poolID)
 
 	return nil
 }
@@ -201,7 +203,7 @@ func resourceVirtualEnvironmentPoolDelete(d *schema.ResourceData, m interface{})
 	err = veClient.DeletePool(poolID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -47,6 +47,9 @@ func resourceVirtualEnvironmentTime() *schema.Resource {
 		Read:   resourceVirtualEnvironmentTimeRead,
 		Update: resourceVirtualEnvironmentTimeUpdate,
 		Delete: resourceVirtualEnvironmentTimeDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVirtualEnvironmentTimeImport,
+		},
 	}
 }
 
@@ -64,6 +67,15 @@ func resourceVirtualEnvironmentTimeCreate(d *schema.ResourceData, m interface{})
 	return nil
 }
 
+func resourceVirtualEnvironmentTimeImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	nodeName := d.Id()
+
+	d.SetId(fmt.Sprintf("%s_time", nodeName))
+	d.Set(mkResourceVirtualEnvironmentTimeNodeName, nodeName)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceVirtualEnvironmentTimeRead(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
@@ -76,6 +88,12 @@ func resourceVirtualEnvironmentTimeRead(d *schema.ResourceData, m interface{}) e
 	nodeTime, err := veClient.GetNodeTime(nodeName)
 
 	if err != nil {
+		if proxmox.IsNotFound(err) {
+			d.SetId("")
+
+			return nil
+		}
+
 		return err
 	}
 
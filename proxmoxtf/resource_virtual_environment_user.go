@@ -5,7 +5,6 @@
 package proxmoxtf
 
 import (
-	"strings"
 	"time"
 
 	"github.com/danitso/terraform-provider-proxmox/proxmox"
@@ -136,6 +135,9 @@ func resourceVirtualEnvironmentUser() *schema.Resource {
 		Read:   resourceVirtualEnvironmentUserRead,
 		Update: resourceVirtualEnvironmentUserUpdate,
 		Delete: resourceVirtualEnvironmentUserDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -228,7 +230,7 @@ func resourceVirtualEnvironmentUserRead(d *schema.ResourceData, m interface{}) e
 	user, err := veClient.GetUser(userID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -264,6 +266,7 @@ func resourceVirtualEnvironmentUserRead(d *schema.ResourceData, m interface{}) e
 	}
 
 	d.Set(mkResourceVirtualEnvironmentUserACL, aclParsed)
+	d.Set(mkResourceVirtualEnvironmentUserUserID, userID)
 
 	if user.Comment != nil {
 		d.Set(mkResourceVirtualEnvironmentUserComment, user.Comment)
@@ -458,7 +461,7 @@ func resourceVirtualEnvironmentUserDelete(d *schema.ResourceData, m interface{})
 	err = veClient.DeleteUser(userID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
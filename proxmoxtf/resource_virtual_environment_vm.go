@@ -5,8 +5,11 @@
 package proxmoxtf
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -38,6 +41,7 @@ const (
 	dvResourceVirtualEnvironmentVMCPUArchitecture                   = "x86_64"
 	dvResourceVirtualEnvironmentVMCPUCores                          = 1
 	dvResourceVirtualEnvironmentVMCPUHotplugged                     = 0
+	dvResourceVirtualEnvironmentVMCPUNUMA                           = false
 	dvResourceVirtualEnvironmentVMCPUSockets                        = 1
 	dvResourceVirtualEnvironmentVMCPUType                           = "qemu64"
 	dvResourceVirtualEnvironmentVMCPUUnits                          = 1024
@@ -51,6 +55,14 @@ const (
 	dvResourceVirtualEnvironmentVMDiskSpeedReadBurstable            = 0
 	dvResourceVirtualEnvironmentVMDiskSpeedWrite                    = 0
 	dvResourceVirtualEnvironmentVMDiskSpeedWriteBurstable           = 0
+	dvResourceVirtualEnvironmentVMEFIDiskDatastoreID                = "local-lvm"
+	dvResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys            = false
+	dvResourceVirtualEnvironmentVMEFIDiskType                       = "4m"
+	dvResourceVirtualEnvironmentVMHostPCIDeviceMDev                 = ""
+	dvResourceVirtualEnvironmentVMHostPCIDevicePCIE                 = false
+	dvResourceVirtualEnvironmentVMHostPCIDeviceROMBAR               = true
+	dvResourceVirtualEnvironmentVMHostPCIDeviceROMFile              = ""
+	dvResourceVirtualEnvironmentVMHostPCIDeviceXVGA                 = false
 	dvResourceVirtualEnvironmentVMInitializationDatastoreID         = "local-lvm"
 	dvResourceVirtualEnvironmentVMInitializationDNSDomain           = ""
 	dvResourceVirtualEnvironmentVMInitializationDNSServer           = ""
@@ -65,6 +77,8 @@ const (
 	dvResourceVirtualEnvironmentVMMemoryDedicated                   = 512
 	dvResourceVirtualEnvironmentVMMemoryFloating                    = 0
 	dvResourceVirtualEnvironmentVMMemoryShared                      = 0
+	dvResourceVirtualEnvironmentVMMigrationTargetDatastoreID        = ""
+	dvResourceVirtualEnvironmentVMMigrationWithLocalDisks           = false
 	dvResourceVirtualEnvironmentVMName                              = ""
 	dvResourceVirtualEnvironmentVMNetworkDeviceBridge               = "vmbr0"
 	dvResourceVirtualEnvironmentVMNetworkDeviceEnabled              = true
@@ -72,26 +86,46 @@ const (
 	dvResourceVirtualEnvironmentVMNetworkDeviceModel                = "virtio"
 	dvResourceVirtualEnvironmentVMNetworkDeviceRateLimit            = 0
 	dvResourceVirtualEnvironmentVMNetworkDeviceVLANID               = 0
+	dvResourceVirtualEnvironmentVMNUMADeviceHostNodes               = ""
+	dvResourceVirtualEnvironmentVMNUMADevicePolicy                  = "preferred"
 	dvResourceVirtualEnvironmentVMOperatingSystemType               = "other"
 	dvResourceVirtualEnvironmentVMPoolID                            = ""
+	dvResourceVirtualEnvironmentVMRNGDeviceMaxBytes                 = 1024
+	dvResourceVirtualEnvironmentVMRNGDevicePeriod                   = 1000
+	dvResourceVirtualEnvironmentVMRNGDeviceSource                   = "/dev/urandom"
 	dvResourceVirtualEnvironmentVMSerialDeviceDevice                = "socket"
+	dvResourceVirtualEnvironmentVMSMBIOSFamily                      = ""
+	dvResourceVirtualEnvironmentVMSMBIOSManufacturer                = ""
+	dvResourceVirtualEnvironmentVMSMBIOSProduct                     = ""
+	dvResourceVirtualEnvironmentVMSMBIOSSerial                      = ""
+	dvResourceVirtualEnvironmentVMSMBIOSSKU                         = ""
+	dvResourceVirtualEnvironmentVMSMBIOSVersion                     = ""
 	dvResourceVirtualEnvironmentVMStarted                           = true
 	dvResourceVirtualEnvironmentVMTabletDevice                      = true
 	dvResourceVirtualEnvironmentVMTemplate                          = false
 	dvResourceVirtualEnvironmentVMTimeoutClone                      = 1800
+	dvResourceVirtualEnvironmentVMTimeoutMigrate                    = 1800
 	dvResourceVirtualEnvironmentVMTimeoutMoveDisk                   = 1800
 	dvResourceVirtualEnvironmentVMTimeoutReboot                     = 1800
 	dvResourceVirtualEnvironmentVMTimeoutShutdownVM                 = 1800
 	dvResourceVirtualEnvironmentVMTimeoutStartVM                    = 1800
 	dvResourceVirtualEnvironmentVMTimeoutStopVM                     = 300
+	dvResourceVirtualEnvironmentVMTPMStateDatastoreID               = "local-lvm"
+	dvResourceVirtualEnvironmentVMTPMStateVersion                   = "v2.0"
+	dvResourceVirtualEnvironmentVMUSBDeviceUSB3                     = false
 	dvResourceVirtualEnvironmentVMVGAEnabled                        = true
 	dvResourceVirtualEnvironmentVMVGAMemory                         = 16
 	dvResourceVirtualEnvironmentVMVGAType                           = "std"
 	dvResourceVirtualEnvironmentVMVMID                              = -1
+	dvResourceVirtualEnvironmentVMWatchdogDeviceAction              = "reset"
+	dvResourceVirtualEnvironmentVMWatchdogDeviceModel               = "i6300esb"
 
 	maxResourceVirtualEnvironmentVMAudioDevices   = 1
+	maxResourceVirtualEnvironmentVMHostPCIDevices = 16
 	maxResourceVirtualEnvironmentVMNetworkDevices = 8
+	maxResourceVirtualEnvironmentVMNUMADevices    = 8
 	maxResourceVirtualEnvironmentVMSerialDevices  = 4
+	maxResourceVirtualEnvironmentVMUSBDevices     = 5
 
 	mkResourceVirtualEnvironmentVMRebootAfterCreation               = "reboot"
 	mkResourceVirtualEnvironmentVMOnBoot                            = "on_boot"
@@ -120,6 +154,7 @@ const (
 	mkResourceVirtualEnvironmentVMCPUCores                          = "cores"
 	mkResourceVirtualEnvironmentVMCPUFlags                          = "flags"
 	mkResourceVirtualEnvironmentVMCPUHotplugged                     = "hotplugged"
+	mkResourceVirtualEnvironmentVMCPUNUMA                           = "numa"
 	mkResourceVirtualEnvironmentVMCPUSockets                        = "sockets"
 	mkResourceVirtualEnvironmentVMCPUType                           = "type"
 	mkResourceVirtualEnvironmentVMCPUUnits                          = "units"
@@ -135,6 +170,18 @@ const (
 	mkResourceVirtualEnvironmentVMDiskSpeedReadBurstable            = "read_burstable"
 	mkResourceVirtualEnvironmentVMDiskSpeedWrite                    = "write"
 	mkResourceVirtualEnvironmentVMDiskSpeedWriteBurstable           = "write_burstable"
+	mkResourceVirtualEnvironmentVMEFIDisk                           = "efi_disk"
+	mkResourceVirtualEnvironmentVMEFIDiskDatastoreID                = "datastore_id"
+	mkResourceVirtualEnvironmentVMEFIDiskFileFormat                 = "file_format"
+	mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys            = "pre_enrolled_keys"
+	mkResourceVirtualEnvironmentVMEFIDiskType                       = "type"
+	mkResourceVirtualEnvironmentVMHostPCIDevice                     = "hostpci"
+	mkResourceVirtualEnvironmentVMHostPCIDeviceID                   = "id"
+	mkResourceVirtualEnvironmentVMHostPCIDeviceMDev                 = "mdev"
+	mkResourceVirtualEnvironmentVMHostPCIDevicePCIE                 = "pcie"
+	mkResourceVirtualEnvironmentVMHostPCIDeviceROMBAR               = "rombar"
+	mkResourceVirtualEnvironmentVMHostPCIDeviceROMFile              = "rom_file"
+	mkResourceVirtualEnvironmentVMHostPCIDeviceXVGA                 = "xvga"
 	mkResourceVirtualEnvironmentVMInitialization                    = "initialization"
 	mkResourceVirtualEnvironmentVMInitializationDatastoreID         = "datastore_id"
 	mkResourceVirtualEnvironmentVMInitializationDNS                 = "dns"
@@ -161,6 +208,10 @@ const (
 	mkResourceVirtualEnvironmentVMMemoryDedicated                   = "dedicated"
 	mkResourceVirtualEnvironmentVMMemoryFloating                    = "floating"
 	mkResourceVirtualEnvironmentVMMemoryShared                      = "shared"
+	mkResourceVirtualEnvironmentVMMigration                         = "migration"
+	mkResourceVirtualEnvironmentVMMigrationDatastoreMapping         = "datastore_mapping"
+	mkResourceVirtualEnvironmentVMMigrationTargetDatastoreID        = "target_datastore_id"
+	mkResourceVirtualEnvironmentVMMigrationWithLocalDisks           = "with_local_disks"
 	mkResourceVirtualEnvironmentVMName                              = "name"
 	mkResourceVirtualEnvironmentVMNetworkDevice                     = "network_device"
 	mkResourceVirtualEnvironmentVMNetworkDeviceBridge               = "bridge"
@@ -171,25 +222,52 @@ const (
 	mkResourceVirtualEnvironmentVMNetworkDeviceVLANID               = "vlan_id"
 	mkResourceVirtualEnvironmentVMNetworkInterfaceNames             = "network_interface_names"
 	mkResourceVirtualEnvironmentVMNodeName                          = "node_name"
+	mkResourceVirtualEnvironmentVMNUMADevice                        = "numa"
+	mkResourceVirtualEnvironmentVMNUMADeviceCPUs                    = "cpus"
+	mkResourceVirtualEnvironmentVMNUMADeviceHostNodes               = "host_nodes"
+	mkResourceVirtualEnvironmentVMNUMADeviceMemory                  = "memory"
+	mkResourceVirtualEnvironmentVMNUMADevicePolicy                  = "policy"
 	mkResourceVirtualEnvironmentVMOperatingSystem                   = "operating_system"
 	mkResourceVirtualEnvironmentVMOperatingSystemType               = "type"
 	mkResourceVirtualEnvironmentVMPoolID                            = "pool_id"
+	mkResourceVirtualEnvironmentVMRNGDevice                         = "rng"
+	mkResourceVirtualEnvironmentVMRNGDeviceMaxBytes                 = "max_bytes"
+	mkResourceVirtualEnvironmentVMRNGDevicePeriod                   = "period"
+	mkResourceVirtualEnvironmentVMRNGDeviceSource                   = "source"
 	mkResourceVirtualEnvironmentVMSerialDevice                      = "serial_device"
 	mkResourceVirtualEnvironmentVMSerialDeviceDevice                = "device"
+	mkResourceVirtualEnvironmentVMSMBIOS                            = "smbios"
+	mkResourceVirtualEnvironmentVMSMBIOSFamily                      = "family"
+	mkResourceVirtualEnvironmentVMSMBIOSManufacturer                = "manufacturer"
+	mkResourceVirtualEnvironmentVMSMBIOSProduct                     = "product"
+	mkResourceVirtualEnvironmentVMSMBIOSSerial                      = "serial"
+	mkResourceVirtualEnvironmentVMSMBIOSSKU                         = "sku"
+	mkResourceVirtualEnvironmentVMSMBIOSUUID                        = "uuid"
+	mkResourceVirtualEnvironmentVMSMBIOSVersion                     = "version"
 	mkResourceVirtualEnvironmentVMStarted                           = "started"
 	mkResourceVirtualEnvironmentVMTabletDevice                      = "tablet_device"
 	mkResourceVirtualEnvironmentVMTemplate                          = "template"
 	mkResourceVirtualEnvironmentVMTimeoutClone                      = "timeout_clone"
+	mkResourceVirtualEnvironmentVMTimeoutMigrate                    = "timeout_migrate"
 	mkResourceVirtualEnvironmentVMTimeoutMoveDisk                   = "timeout_move_disk"
 	mkResourceVirtualEnvironmentVMTimeoutReboot                     = "timeout_reboot"
 	mkResourceVirtualEnvironmentVMTimeoutShutdownVM                 = "timeout_shutdown_vm"
 	mkResourceVirtualEnvironmentVMTimeoutStartVM                    = "timeout_start_vm"
 	mkResourceVirtualEnvironmentVMTimeoutStopVM                     = "timeout_stop_vm"
+	mkResourceVirtualEnvironmentVMTPMState                          = "tpm_state"
+	mkResourceVirtualEnvironmentVMTPMStateDatastoreID               = "datastore_id"
+	mkResourceVirtualEnvironmentVMTPMStateVersion                   = "version"
+	mkResourceVirtualEnvironmentVMUSBDevice                         = "usb"
+	mkResourceVirtualEnvironmentVMUSBDeviceHost                     = "host"
+	mkResourceVirtualEnvironmentVMUSBDeviceUSB3                     = "usb3"
 	mkResourceVirtualEnvironmentVMVGA                               = "vga"
 	mkResourceVirtualEnvironmentVMVGAEnabled                        = "enabled"
 	mkResourceVirtualEnvironmentVMVGAMemory                         = "memory"
 	mkResourceVirtualEnvironmentVMVGAType                           = "type"
 	mkResourceVirtualEnvironmentVMVMID                              = "vm_id"
+	mkResourceVirtualEnvironmentVMWatchdogDevice                    = "watchdog"
+	mkResourceVirtualEnvironmentVMWatchdogDeviceAction              = "action"
+	mkResourceVirtualEnvironmentVMWatchdogDeviceModel               = "model"
 )
 
 func resourceVirtualEnvironmentVM() *schema.Resource {
@@ -348,6 +426,7 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 							Optional:    true,
 							ForceNew:    true,
 							Default:     dvResourceVirtualEnvironmentVMCloneRetries,
+							Deprecated:  "Transient errors are now retried according to the virtual_environment.max_retries argument of the provider",
 						},
 						mkResourceVirtualEnvironmentVMCloneDatastoreID: {
 							Type:        schema.TypeString,
@@ -393,6 +472,7 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 							mkResourceVirtualEnvironmentVMCPUCores:        dvResourceVirtualEnvironmentVMCPUCores,
 							mkResourceVirtualEnvironmentVMCPUFlags:        []interface{}{},
 							mkResourceVirtualEnvironmentVMCPUHotplugged:   dvResourceVirtualEnvironmentVMCPUHotplugged,
+							mkResourceVirtualEnvironmentVMCPUNUMA:         dvResourceVirtualEnvironmentVMCPUNUMA,
 							mkResourceVirtualEnvironmentVMCPUSockets:      dvResourceVirtualEnvironmentVMCPUSockets,
 							mkResourceVirtualEnvironmentVMCPUType:         dvResourceVirtualEnvironmentVMCPUType,
 							mkResourceVirtualEnvironmentVMCPUUnits:        dvResourceVirtualEnvironmentVMCPUUnits,
@@ -431,6 +511,12 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 							Default:      dvResourceVirtualEnvironmentVMCPUHotplugged,
 							ValidateFunc: validation.IntBetween(0, 2304),
 						},
+						mkResourceVirtualEnvironmentVMCPUNUMA: {
+							Type:        schema.TypeBool,
+							Description: "Whether to enable NUMA",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMCPUNUMA,
+						},
 						mkResourceVirtualEnvironmentVMCPUSockets: {
 							Type:         schema.TypeInt,
 							Description:  "The number of CPU sockets",
@@ -565,6 +651,94 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 				MaxItems: 14,
 				MinItems: 0,
 			},
+			mkResourceVirtualEnvironmentVMEFIDisk: {
+				Type:        schema.TypeList,
+				Description: "The EFI disk",
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMEFIDiskDatastoreID: {
+							Type:        schema.TypeString,
+							Description: "The datastore id",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMEFIDiskDatastoreID,
+						},
+						mkResourceVirtualEnvironmentVMEFIDiskFileFormat: {
+							Type:         schema.TypeString,
+							Description:  "The file format",
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: getFileFormatValidator(),
+						},
+						mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys: {
+							Type:        schema.TypeBool,
+							Description: "Whether to pre-enroll the distribution specific and Microsoft Secure Boot keys",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys,
+						},
+						mkResourceVirtualEnvironmentVMEFIDiskType: {
+							Type:         schema.TypeString,
+							Description:  "The size of the OVMF variables store",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMEFIDiskType,
+							ValidateFunc: resourceVirtualEnvironmentVMGetEFIDiskTypeValidator(),
+						},
+					},
+				},
+				MaxItems: 1,
+				MinItems: 0,
+			},
+			mkResourceVirtualEnvironmentVMHostPCIDevice: {
+				Type:        schema.TypeList,
+				Description: "The host PCI devices",
+				Optional:    true,
+				DefaultFunc: func() (interface{}, error) {
+					return []interface{}{}, nil
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMHostPCIDeviceID: {
+							Type:         schema.TypeString,
+							Description:  "The PCI device identifier",
+							Required:     true,
+							ValidateFunc: resourceVirtualEnvironmentVMGetHostPCIDeviceIDValidator(),
+						},
+						mkResourceVirtualEnvironmentVMHostPCIDeviceMDev: {
+							Type:        schema.TypeString,
+							Description: "The mediated device type",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMHostPCIDeviceMDev,
+						},
+						mkResourceVirtualEnvironmentVMHostPCIDevicePCIE: {
+							Type:        schema.TypeBool,
+							Description: "Whether to pass the device through as a PCI Express device",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMHostPCIDevicePCIE,
+						},
+						mkResourceVirtualEnvironmentVMHostPCIDeviceROMBAR: {
+							Type:        schema.TypeBool,
+							Description: "Whether to make the ROM of the device visible to the guest",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMHostPCIDeviceROMBAR,
+						},
+						mkResourceVirtualEnvironmentVMHostPCIDeviceROMFile: {
+							Type:        schema.TypeString,
+							Description: "The custom ROM file, which must be located in /usr/share/kvm/",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMHostPCIDeviceROMFile,
+						},
+						mkResourceVirtualEnvironmentVMHostPCIDeviceXVGA: {
+							Type:        schema.TypeBool,
+							Description: "Whether to use the device as the primary GPU of the guest",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMHostPCIDeviceXVGA,
+						},
+					},
+				},
+				MaxItems: maxResourceVirtualEnvironmentVMHostPCIDevices,
+				MinItems: 0,
+			},
 			mkResourceVirtualEnvironmentVMInitialization: {
 				Type:        schema.TypeList,
 				Description: "The cloud-init configuration",
@@ -805,6 +979,38 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 				MaxItems: 1,
 				MinItems: 0,
 			},
+			mkResourceVirtualEnvironmentVMMigration: {
+				Type:        schema.TypeList,
+				Description: "The migration settings, which are used when the node name changes",
+				Optional:    true,
+				DefaultFunc: func() (interface{}, error) {
+					return []interface{}{}, nil
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMMigrationDatastoreMapping: {
+							Type:        schema.TypeMap,
+							Description: "The target datastore for each source datastore",
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						mkResourceVirtualEnvironmentVMMigrationTargetDatastoreID: {
+							Type:        schema.TypeString,
+							Description: "The target datastore for local disks, which are not included in the datastore mapping",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMMigrationTargetDatastoreID,
+						},
+						mkResourceVirtualEnvironmentVMMigrationWithLocalDisks: {
+							Type:        schema.TypeBool,
+							Description: "Whether to migrate local disks",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMMigrationWithLocalDisks,
+						},
+					},
+				},
+				MaxItems: 1,
+				MinItems: 0,
+			},
 			mkResourceVirtualEnvironmentVMName: {
 				Type:        schema.TypeString,
 				Description: "The name",
@@ -876,7 +1082,46 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 				Type:        schema.TypeString,
 				Description: "The node name",
 				Required:    true,
-				ForceNew:    true,
+			},
+			mkResourceVirtualEnvironmentVMNUMADevice: {
+				Type:        schema.TypeList,
+				Description: "The NUMA nodes",
+				Optional:    true,
+				DefaultFunc: func() (interface{}, error) {
+					return []interface{}{}, nil
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMNUMADeviceCPUs: {
+							Type:         schema.TypeString,
+							Description:  "The CPUs assigned to the NUMA node",
+							Required:     true,
+							ValidateFunc: resourceVirtualEnvironmentVMGetNUMADeviceRangeValidator(),
+						},
+						mkResourceVirtualEnvironmentVMNUMADeviceHostNodes: {
+							Type:         schema.TypeString,
+							Description:  "The host NUMA nodes to use",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMNUMADeviceHostNodes,
+							ValidateFunc: resourceVirtualEnvironmentVMGetNUMADeviceRangeValidator(),
+						},
+						mkResourceVirtualEnvironmentVMNUMADeviceMemory: {
+							Type:         schema.TypeInt,
+							Description:  "The amount of memory provided by the NUMA node",
+							Required:     true,
+							ValidateFunc: validation.IntBetween(1, 268435456),
+						},
+						mkResourceVirtualEnvironmentVMNUMADevicePolicy: {
+							Type:         schema.TypeString,
+							Description:  "The NUMA allocation policy",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMNUMADevicePolicy,
+							ValidateFunc: resourceVirtualEnvironmentVMGetNUMADevicePolicyValidator(),
+						},
+					},
+				},
+				MaxItems: maxResourceVirtualEnvironmentVMNUMADevices,
+				MinItems: 0,
 			},
 			mkResourceVirtualEnvironmentVMOperatingSystem: {
 				Type:        schema.TypeList,
@@ -910,6 +1155,41 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 				ForceNew:    true,
 				Default:     dvResourceVirtualEnvironmentVMPoolID,
 			},
+			mkResourceVirtualEnvironmentVMRNGDevice: {
+				Type:        schema.TypeList,
+				Description: "The VirtIO RNG device",
+				Optional:    true,
+				DefaultFunc: func() (interface{}, error) {
+					return []interface{}{}, nil
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMRNGDeviceMaxBytes: {
+							Type:         schema.TypeInt,
+							Description:  "The maximum number of bytes to inject into the guest per period (0 to disable the limit)",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMRNGDeviceMaxBytes,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						mkResourceVirtualEnvironmentVMRNGDevicePeriod: {
+							Type:         schema.TypeInt,
+							Description:  "The period in milliseconds",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMRNGDevicePeriod,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						mkResourceVirtualEnvironmentVMRNGDeviceSource: {
+							Type:         schema.TypeString,
+							Description:  "The entropy source on the host",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMRNGDeviceSource,
+							ValidateFunc: resourceVirtualEnvironmentVMGetRNGDeviceSourceValidator(),
+						},
+					},
+				},
+				MaxItems: 1,
+				MinItems: 0,
+			},
 			mkResourceVirtualEnvironmentVMSerialDevice: {
 				Type:        schema.TypeList,
 				Description: "The serial devices",
@@ -935,6 +1215,61 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 				MaxItems: maxResourceVirtualEnvironmentVMSerialDevices,
 				MinItems: 0,
 			},
+			mkResourceVirtualEnvironmentVMSMBIOS: {
+				Type:        schema.TypeList,
+				Description: "The SMBIOS (type 1) settings",
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMSMBIOSFamily: {
+							Type:        schema.TypeString,
+							Description: "The family",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMSMBIOSFamily,
+						},
+						mkResourceVirtualEnvironmentVMSMBIOSManufacturer: {
+							Type:        schema.TypeString,
+							Description: "The manufacturer",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMSMBIOSManufacturer,
+						},
+						mkResourceVirtualEnvironmentVMSMBIOSProduct: {
+							Type:        schema.TypeString,
+							Description: "The product",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMSMBIOSProduct,
+						},
+						mkResourceVirtualEnvironmentVMSMBIOSSerial: {
+							Type:        schema.TypeString,
+							Description: "The serial number",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMSMBIOSSerial,
+						},
+						mkResourceVirtualEnvironmentVMSMBIOSSKU: {
+							Type:        schema.TypeString,
+							Description: "The SKU number",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMSMBIOSSKU,
+						},
+						mkResourceVirtualEnvironmentVMSMBIOSUUID: {
+							Type:         schema.TypeString,
+							Description:  "The UUID",
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.IsUUID,
+						},
+						mkResourceVirtualEnvironmentVMSMBIOSVersion: {
+							Type:        schema.TypeString,
+							Description: "The version",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMSMBIOSVersion,
+						},
+					},
+				},
+				MaxItems: 1,
+				MinItems: 0,
+			},
 			mkResourceVirtualEnvironmentVMStarted: {
 				Type:        schema.TypeBool,
 				Description: "Whether to start the virtual machine",
@@ -963,6 +1298,12 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 				Optional:    true,
 				Default:     dvResourceVirtualEnvironmentVMTimeoutClone,
 			},
+			mkResourceVirtualEnvironmentVMTimeoutMigrate: {
+				Type:        schema.TypeInt,
+				Description: "Migrate VM timeout",
+				Optional:    true,
+				Default:     dvResourceVirtualEnvironmentVMTimeoutMigrate,
+			},
 			mkResourceVirtualEnvironmentVMTimeoutMoveDisk: {
 				Type:        schema.TypeInt,
 				Description: "MoveDisk timeout",
@@ -993,6 +1334,57 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 				Optional:    true,
 				Default:     dvResourceVirtualEnvironmentVMTimeoutStopVM,
 			},
+			mkResourceVirtualEnvironmentVMTPMState: {
+				Type:        schema.TypeList,
+				Description: "The TPM state",
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMTPMStateDatastoreID: {
+							Type:        schema.TypeString,
+							Description: "The datastore id",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMTPMStateDatastoreID,
+						},
+						mkResourceVirtualEnvironmentVMTPMStateVersion: {
+							Type:         schema.TypeString,
+							Description:  "The TPM version",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMTPMStateVersion,
+							ValidateFunc: resourceVirtualEnvironmentVMGetTPMStateVersionValidator(),
+						},
+					},
+				},
+				MaxItems: 1,
+				MinItems: 0,
+			},
+			mkResourceVirtualEnvironmentVMUSBDevice: {
+				Type:        schema.TypeList,
+				Description: "The USB devices",
+				Optional:    true,
+				DefaultFunc: func() (interface{}, error) {
+					return []interface{}{}, nil
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMUSBDeviceHost: {
+							Type:         schema.TypeString,
+							Description:  "The host device (vendor:product, bus-port or spice)",
+							Required:     true,
+							ValidateFunc: resourceVirtualEnvironmentVMGetUSBDeviceHostValidator(),
+						},
+						mkResourceVirtualEnvironmentVMUSBDeviceUSB3: {
+							Type:        schema.TypeBool,
+							Description: "Whether to use a USB 3 controller",
+							Optional:    true,
+							Default:     dvResourceVirtualEnvironmentVMUSBDeviceUSB3,
+						},
+					},
+				},
+				MaxItems: maxResourceVirtualEnvironmentVMUSBDevices,
+				MinItems: 0,
+			},
 			mkResourceVirtualEnvironmentVMVGA: {
 				Type:        schema.TypeList,
 				Description: "The VGA configuration",
@@ -1041,25 +1433,66 @@ func resourceVirtualEnvironmentVM() *schema.Resource {
 				Default:      dvResourceVirtualEnvironmentVMVMID,
 				ValidateFunc: getVMIDValidator(),
 			},
+			mkResourceVirtualEnvironmentVMWatchdogDevice: {
+				Type:        schema.TypeList,
+				Description: "The watchdog device",
+				Optional:    true,
+				DefaultFunc: func() (interface{}, error) {
+					return []interface{}{}, nil
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						mkResourceVirtualEnvironmentVMWatchdogDeviceAction: {
+							Type:         schema.TypeString,
+							Description:  "The action to perform when the guest fails to poll the watchdog in time",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMWatchdogDeviceAction,
+							ValidateFunc: resourceVirtualEnvironmentVMGetWatchdogDeviceActionValidator(),
+						},
+						mkResourceVirtualEnvironmentVMWatchdogDeviceModel: {
+							Type:         schema.TypeString,
+							Description:  "The watchdog model",
+							Optional:     true,
+							Default:      dvResourceVirtualEnvironmentVMWatchdogDeviceModel,
+							ValidateFunc: resourceVirtualEnvironmentVMGetWatchdogDeviceModelValidator(),
+						},
+					},
+				},
+				MaxItems: 1,
+				MinItems: 0,
+			},
+		},
+		Create:        resourceVirtualEnvironmentVMCreate,
+		Read:          resourceVirtualEnvironmentVMRead,
+		Update:        resourceVirtualEnvironmentVMUpdate,
+		Delete:        resourceVirtualEnvironmentVMDelete,
+		CustomizeDiff: resourceVirtualEnvironmentVMCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: resourceVirtualEnvironmentVMImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(120 * time.Minute),
+			Update: schema.DefaultTimeout(120 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
 		},
-		Create: resourceVirtualEnvironmentVMCreate,
-		Read:   resourceVirtualEnvironmentVMRead,
-		Update: resourceVirtualEnvironmentVMUpdate,
-		Delete: resourceVirtualEnvironmentVMDelete,
 	}
 }
 
 func resourceVirtualEnvironmentVMCreate(d *schema.ResourceData, m interface{}) error {
+	config := m.(providerConfiguration)
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
+
 	clone := d.Get(mkResourceVirtualEnvironmentVMClone).([]interface{})
 
 	if len(clone) > 0 {
-		return resourceVirtualEnvironmentVMCreateClone(d, m)
+		return resourceVirtualEnvironmentVMCreateClone(ctx, d, m)
 	}
 
-	return resourceVirtualEnvironmentVMCreateCustom(d, m)
+	return resourceVirtualEnvironmentVMCreateCustom(ctx, d, m)
 }
 
-func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface{}) error {
+func resourceVirtualEnvironmentVMCreateClone(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
 
@@ -1069,7 +1502,6 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 
 	clone := d.Get(mkResourceVirtualEnvironmentVMClone).([]interface{})
 	cloneBlock := clone[0].(map[string]interface{})
-	cloneRetries := cloneBlock[mkResourceVirtualEnvironmentVMCloneRetries].(int)
 	cloneDatastoreID := cloneBlock[mkResourceVirtualEnvironmentVMCloneDatastoreID].(string)
 	cloneNodeName := cloneBlock[mkResourceVirtualEnvironmentVMCloneNodeName].(string)
 	cloneVMID := cloneBlock[mkResourceVirtualEnvironmentVMCloneVMID].(int)
@@ -1119,9 +1551,9 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 	if cloneNodeName != "" && cloneNodeName != nodeName {
 		cloneBody.TargetNodeName = &nodeName
 
-		err = veClient.CloneVM(cloneNodeName, cloneVMID, cloneRetries, cloneBody, cloneTimeout)
+		err = veClient.CloneVMContext(ctx, cloneNodeName, cloneVMID, cloneBody, cloneTimeout)
 	} else {
-		err = veClient.CloneVM(nodeName, cloneVMID, cloneRetries, cloneBody, cloneTimeout)
+		err = veClient.CloneVMContext(ctx, nodeName, cloneVMID, cloneBody, cloneTimeout)
 	}
 
 	if err != nil {
@@ -1131,7 +1563,7 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 	d.SetId(strconv.Itoa(vmID))
 
 	// Wait for the virtual machine to be created and its configuration lock to be released.
-	err = veClient.WaitForVMConfigUnlock(nodeName, vmID, 600, 5, true)
+	err = veClient.WaitForVMConfigUnlockContext(ctx, nodeName, vmID, 600, 5, true)
 
 	if err != nil {
 		return err
@@ -1149,16 +1581,22 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 	bios := d.Get(mkResourceVirtualEnvironmentVMBIOS).(string)
 	cdrom := d.Get(mkResourceVirtualEnvironmentVMCDROM).([]interface{})
 	cpu := d.Get(mkResourceVirtualEnvironmentVMCPU).([]interface{})
+	hostPCIDevice := d.Get(mkResourceVirtualEnvironmentVMHostPCIDevice).([]interface{})
 	initialization := d.Get(mkResourceVirtualEnvironmentVMInitialization).([]interface{})
 	keyboardLayout := d.Get(mkResourceVirtualEnvironmentVMKeyboardLayout).(string)
 	memory := d.Get(mkResourceVirtualEnvironmentVMMemory).([]interface{})
 	networkDevice := d.Get(mkResourceVirtualEnvironmentVMNetworkDevice).([]interface{})
+	numaDevice := d.Get(mkResourceVirtualEnvironmentVMNUMADevice).([]interface{})
 	operatingSystem := d.Get(mkResourceVirtualEnvironmentVMOperatingSystem).([]interface{})
+	rngDevice := d.Get(mkResourceVirtualEnvironmentVMRNGDevice).([]interface{})
 	serialDevice := d.Get(mkResourceVirtualEnvironmentVMSerialDevice).([]interface{})
+	smbios := d.Get(mkResourceVirtualEnvironmentVMSMBIOS).([]interface{})
 	onBoot := proxmox.CustomBool(d.Get(mkResourceVirtualEnvironmentVMOnBoot).(bool))
 	tabletDevice := proxmox.CustomBool(d.Get(mkResourceVirtualEnvironmentVMTabletDevice).(bool))
 	template := proxmox.CustomBool(d.Get(mkResourceVirtualEnvironmentVMTemplate).(bool))
+	usbDevice := d.Get(mkResourceVirtualEnvironmentVMUSBDevice).([]interface{})
 	vga := d.Get(mkResourceVirtualEnvironmentVMVGA).([]interface{})
+	watchdogDevice := d.Get(mkResourceVirtualEnvironmentVMWatchdogDevice).([]interface{})
 
 	updateBody := &proxmox.VirtualEnvironmentVMUpdateRequestBody{
 		AudioDevices: audioDevices,
@@ -1235,6 +1673,7 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 		cpuCores := cpuBlock[mkResourceVirtualEnvironmentVMCPUCores].(int)
 		cpuFlags := cpuBlock[mkResourceVirtualEnvironmentVMCPUFlags].([]interface{})
 		cpuHotplugged := cpuBlock[mkResourceVirtualEnvironmentVMCPUHotplugged].(int)
+		cpuNUMA := proxmox.CustomBool(cpuBlock[mkResourceVirtualEnvironmentVMCPUNUMA].(bool))
 		cpuSockets := cpuBlock[mkResourceVirtualEnvironmentVMCPUSockets].(int)
 		cpuType := cpuBlock[mkResourceVirtualEnvironmentVMCPUType].(string)
 		cpuUnits := cpuBlock[mkResourceVirtualEnvironmentVMCPUUnits].(int)
@@ -1257,12 +1696,25 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 		}
 		updateBody.CPUSockets = &cpuSockets
 		updateBody.CPUUnits = &cpuUnits
+		updateBody.NUMAEnabled = &cpuNUMA
 
 		if cpuHotplugged > 0 {
 			updateBody.VirtualCPUCount = &cpuHotplugged
 		}
 	}
 
+	if len(hostPCIDevice) > 0 {
+		updateBody.PCIDevices, err = resourceVirtualEnvironmentVMGetHostPCIDeviceObjects(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		for i := len(updateBody.PCIDevices); i < maxResourceVirtualEnvironmentVMHostPCIDevices; i++ {
+			delete = append(delete, fmt.Sprintf("hostpci%d", i))
+		}
+	}
+
 	if len(initialization) > 0 {
 		initializationBlock := initialization[0].(map[string]interface{})
 		initializationDatastoreID := initializationBlock[mkResourceVirtualEnvironmentVMInitializationDatastoreID].(string)
@@ -1334,6 +1786,18 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 		}
 	}
 
+	if len(numaDevice) > 0 {
+		updateBody.NUMADevices, err = resourceVirtualEnvironmentVMGetNUMADeviceObjects(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		for i := len(updateBody.NUMADevices); i < maxResourceVirtualEnvironmentVMNUMADevices; i++ {
+			delete = append(delete, fmt.Sprintf("numa%d", i))
+		}
+	}
+
 	if len(operatingSystem) > 0 {
 		operatingSystemBlock := operatingSystem[0].(map[string]interface{})
 		operatingSystemType := operatingSystemBlock[mkResourceVirtualEnvironmentVMOperatingSystemType].(string)
@@ -1353,6 +1817,22 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 		}
 	}
 
+	if len(rngDevice) > 0 {
+		updateBody.RNGDevice, err = resourceVirtualEnvironmentVMGetRNGDeviceObject(d, m)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(smbios) > 0 {
+		updateBody.SMBIOS, err = resourceVirtualEnvironmentVMGetSMBIOSObject(d, m)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	updateBody.StartOnBoot = &onBoot
 
 	if tabletDevice != dvResourceVirtualEnvironmentVMTabletDevice {
@@ -1363,6 +1843,18 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 		updateBody.Template = &template
 	}
 
+	if len(usbDevice) > 0 {
+		updateBody.USBDevices, err = resourceVirtualEnvironmentVMGetUSBDeviceObjects(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		for i := len(updateBody.USBDevices); i < maxResourceVirtualEnvironmentVMUSBDevices; i++ {
+			delete = append(delete, fmt.Sprintf("usb%d", i))
+		}
+	}
+
 	if len(vga) > 0 {
 		vgaDevice, err := resourceVirtualEnvironmentVMGetVGADeviceObject(d, m)
 
@@ -1373,6 +1865,14 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 		updateBody.VGADevice = vgaDevice
 	}
 
+	if len(watchdogDevice) > 0 {
+		updateBody.WatchdogDevice, err = resourceVirtualEnvironmentVMGetWatchdogDeviceObject(d, m)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	updateBody.Delete = delete
 
 	err = veClient.UpdateVM(nodeName, vmID, updateBody)
@@ -1385,8 +1885,7 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 	vmConfig, err := veClient.GetVM(nodeName, vmID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") ||
-			(strings.Contains(err.Error(), "HTTP 500") && strings.Contains(err.Error(), "does not exist")) {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -1465,7 +1964,7 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 
 		if dataStoreID != "" {
 			moveDiskTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutMoveDisk).(int)
-			err = veClient.MoveVMDisk(nodeName, vmID, diskMoveBody, moveDiskTimeout)
+			err = veClient.MoveVMDiskContext(ctx, nodeName, vmID, diskMoveBody, moveDiskTimeout)
 
 			if err != nil {
 				return err
@@ -1479,10 +1978,78 @@ func resourceVirtualEnvironmentVMCreateClone(d *schema.ResourceData, m interface
 		}
 	}
 
-	return resourceVirtualEnvironmentVMCreateStart(d, m)
-}
+	// Attach, recreate or move the EFI disk and the TPM state, if the cloned VM does not match the configuration.
+	efiDiskObject, err := resourceVirtualEnvironmentVMGetEFIDiskObject(d, m)
 
-func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interface{}) error {
+	if err != nil {
+		return err
+	}
+
+	tpmStateObject, err := resourceVirtualEnvironmentVMGetTPMStateObject(d, m)
+
+	if err != nil {
+		return err
+	}
+
+	moveDiskTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutMoveDisk).(int)
+
+	if efiDiskObject != nil {
+		efiDiskDatastoreID := resourceVirtualEnvironmentVMGetVolumeDatastoreID(efiDiskObject.FileVolume)
+
+		if vmConfig.EFIDisk == nil {
+			err = veClient.UpdateVM(nodeName, vmID, &proxmox.VirtualEnvironmentVMUpdateRequestBody{
+				EFIDisk: efiDiskObject,
+			})
+		} else if resourceVirtualEnvironmentVMGetEFIDiskRecreationRequired(vmConfig.EFIDisk, efiDiskObject) {
+			err = resourceVirtualEnvironmentVMRecreateDisk(veClient, nodeName, vmID, "efidisk0", &proxmox.VirtualEnvironmentVMUpdateRequestBody{
+				EFIDisk: efiDiskObject,
+			})
+		} else if resourceVirtualEnvironmentVMGetVolumeDatastoreID(vmConfig.EFIDisk.FileVolume) != efiDiskDatastoreID {
+			deleteOriginalDisk := proxmox.CustomBool(true)
+
+			err = veClient.MoveVMDiskContext(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentVMMoveDiskRequestBody{
+				DeleteOriginalDisk:  &deleteOriginalDisk,
+				Disk:                "efidisk0",
+				TargetStorage:       efiDiskDatastoreID,
+				TargetStorageFormat: efiDiskObject.Format,
+			}, moveDiskTimeout)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if tpmStateObject != nil {
+		tpmStateDatastoreID := resourceVirtualEnvironmentVMGetVolumeDatastoreID(tpmStateObject.FileVolume)
+
+		if vmConfig.TPMState == nil {
+			err = veClient.UpdateVM(nodeName, vmID, &proxmox.VirtualEnvironmentVMUpdateRequestBody{
+				TPMState: tpmStateObject,
+			})
+		} else if resourceVirtualEnvironmentVMGetTPMStateRecreationRequired(vmConfig.TPMState, tpmStateObject) {
+			err = resourceVirtualEnvironmentVMRecreateDisk(veClient, nodeName, vmID, "tpmstate0", &proxmox.VirtualEnvironmentVMUpdateRequestBody{
+				TPMState: tpmStateObject,
+			})
+		} else if resourceVirtualEnvironmentVMGetVolumeDatastoreID(vmConfig.TPMState.FileVolume) != tpmStateDatastoreID {
+			deleteOriginalDisk := proxmox.CustomBool(true)
+
+			err = veClient.MoveVMDiskContext(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentVMMoveDiskRequestBody{
+				DeleteOriginalDisk: &deleteOriginalDisk,
+				Disk:               "tpmstate0",
+				TargetStorage:      tpmStateDatastoreID,
+			}, moveDiskTimeout)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceVirtualEnvironmentVMCreateStart(ctx, d, m)
+}
+
+func resourceVirtualEnvironmentVMCreateCustom(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
 
@@ -1538,6 +2105,7 @@ func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interfac
 	cpuCores := cpuBlock[mkResourceVirtualEnvironmentVMCPUCores].(int)
 	cpuFlags := cpuBlock[mkResourceVirtualEnvironmentVMCPUFlags].([]interface{})
 	cpuHotplugged := cpuBlock[mkResourceVirtualEnvironmentVMCPUHotplugged].(int)
+	cpuNUMA := proxmox.CustomBool(cpuBlock[mkResourceVirtualEnvironmentVMCPUNUMA].(bool))
 	cpuSockets := cpuBlock[mkResourceVirtualEnvironmentVMCPUSockets].(int)
 	cpuType := cpuBlock[mkResourceVirtualEnvironmentVMCPUType].(string)
 	cpuUnits := cpuBlock[mkResourceVirtualEnvironmentVMCPUUnits].(int)
@@ -1554,6 +2122,18 @@ func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interfac
 	//ideDeviceObjects := getOrderedDiskDeviceList(diskDeviceObjects, "ide")
 	sataDeviceObjects := diskDeviceObjects["sata"]
 
+	efiDiskObject, err := resourceVirtualEnvironmentVMGetEFIDiskObject(d, m)
+
+	if err != nil {
+		return err
+	}
+
+	hostPCIDeviceObjects, err := resourceVirtualEnvironmentVMGetHostPCIDeviceObjects(d, m)
+
+	if err != nil {
+		return err
+	}
+
 	initializationConfig, err := resourceVirtualEnvironmentVMGetCloudInitConfig(d, m)
 
 	if err != nil {
@@ -1590,6 +2170,12 @@ func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interfac
 
 	nodeName := d.Get(mkResourceVirtualEnvironmentVMNodeName).(string)
 
+	numaDeviceObjects, err := resourceVirtualEnvironmentVMGetNUMADeviceObjects(d, m)
+
+	if err != nil {
+		return err
+	}
+
 	operatingSystem, err := getSchemaBlock(resource, d, m, []string{mkResourceVirtualEnvironmentVMOperatingSystem}, 0, true)
 
 	if err != nil {
@@ -1600,22 +2186,52 @@ func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interfac
 
 	poolID := d.Get(mkResourceVirtualEnvironmentVMPoolID).(string)
 
+	rngDeviceObject, err := resourceVirtualEnvironmentVMGetRNGDeviceObject(d, m)
+
+	if err != nil {
+		return err
+	}
+
 	serialDevices, err := resourceVirtualEnvironmentVMGetSerialDeviceList(d, m)
 
 	if err != nil {
 		return err
 	}
 
+	smbiosObject, err := resourceVirtualEnvironmentVMGetSMBIOSObject(d, m)
+
+	if err != nil {
+		return err
+	}
+
 	onBoot := proxmox.CustomBool(d.Get(mkResourceVirtualEnvironmentVMOnBoot).(bool))
 	tabletDevice := proxmox.CustomBool(d.Get(mkResourceVirtualEnvironmentVMTabletDevice).(bool))
 	template := proxmox.CustomBool(d.Get(mkResourceVirtualEnvironmentVMTemplate).(bool))
 
+	tpmStateObject, err := resourceVirtualEnvironmentVMGetTPMStateObject(d, m)
+
+	if err != nil {
+		return err
+	}
+
+	usbDeviceObjects, err := resourceVirtualEnvironmentVMGetUSBDeviceObjects(d, m)
+
+	if err != nil {
+		return err
+	}
+
 	vgaDevice, err := resourceVirtualEnvironmentVMGetVGADeviceObject(d, m)
 
 	if err != nil {
 		return err
 	}
 
+	watchdogDeviceObject, err := resourceVirtualEnvironmentVMGetWatchdogDeviceObject(d, m)
+
+	if err != nil {
+		return err
+	}
+
 	vmID := d.Get(mkResourceVirtualEnvironmentVMVMID).(int)
 
 	if vmID == -1 {
@@ -1687,19 +2303,33 @@ func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interfac
 		CPUSockets:          &cpuSockets,
 		CPUUnits:            &cpuUnits,
 		DedicatedMemory:     &memoryDedicated,
+		EFIDisk:             efiDiskObject,
 		FloatingMemory:      &memoryFloating,
 		IDEDevices:          ideDevices,
 		KeyboardLayout:      &keyboardLayout,
 		NetworkDevices:      networkDeviceObjects,
+		NUMAEnabled:         &cpuNUMA,
 		OSType:              &operatingSystemType,
+		RNGDevice:           rngDeviceObject,
 		SCSIHardware:        &scsiHardware,
 		SerialDevices:       serialDevices,
 		SharedMemory:        memorySharedObject,
+		SMBIOS:              smbiosObject,
 		StartOnBoot:         &onBoot,
 		TabletDeviceEnabled: &tabletDevice,
 		Template:            &template,
+		TPMState:            tpmStateObject,
 		VGADevice:           vgaDevice,
 		VMID:                &vmID,
+		WatchdogDevice:      watchdogDeviceObject,
+	}
+
+	if len(hostPCIDeviceObjects) > 0 {
+		createBody.PCIDevices = hostPCIDeviceObjects
+	}
+
+	if len(numaDeviceObjects) > 0 {
+		createBody.NUMADevices = numaDeviceObjects
 	}
 
 	if sataDeviceObjects != nil {
@@ -1710,6 +2340,10 @@ func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interfac
 		createBody.SCSIDevices = scsiDeviceObjects
 	}
 
+	if len(usbDeviceObjects) > 0 {
+		createBody.USBDevices = usbDeviceObjects
+	}
+
 	if virtioDeviceObjects != nil {
 		createBody.VirtualIODevices = virtioDeviceObjects
 	}
@@ -1735,7 +2369,7 @@ func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interfac
 		createBody.PoolID = &poolID
 	}
 
-	err = veClient.CreateVM(nodeName, createBody)
+	task, err := veClient.CreateVMAsync(ctx, nodeName, createBody)
 
 	if err != nil {
 		return err
@@ -1743,10 +2377,17 @@ func resourceVirtualEnvironmentVMCreateCustom(d *schema.ResourceData, m interfac
 
 	d.SetId(strconv.Itoa(vmID))
 
-	return resourceVirtualEnvironmentVMCreateCustomDisks(d, m)
+	// Wait for the create task to complete.
+	err = task.WaitContext(ctx, 600, 5)
+
+	if err != nil {
+		return err
+	}
+
+	return resourceVirtualEnvironmentVMCreateCustomDisks(ctx, d, m)
 }
 
-func resourceVirtualEnvironmentVMCreateCustomDisks(d *schema.ResourceData, m interface{}) error {
+func resourceVirtualEnvironmentVMCreateCustomDisks(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
 
@@ -1782,6 +2423,12 @@ func resourceVirtualEnvironmentVMCreateCustomDisks(d *schema.ResourceData, m int
 	diskSchemaResource := diskSchemaElem.(*schema.Resource)
 	diskSpeedResource := diskSchemaResource.Schema[mkResourceVirtualEnvironmentVMDiskSpeed]
 
+	// Disks can be imported through the API with the "import-from" parameter, if supported by the server.
+	var version *proxmox.VirtualEnvironmentVersionResponseData
+
+	importDisks := map[string]proxmox.CustomStorageDevice{}
+	importDiskSizes := map[string]int{}
+
 	// Generate the commands required to import the specified disks.
 	importedDiskCount := 0
 
@@ -1794,6 +2441,14 @@ func resourceVirtualEnvironmentVMCreateCustomDisks(d *schema.ResourceData, m int
 			continue
 		}
 
+		if version == nil {
+			version, err = veClient.Version()
+
+			if err != nil {
+				return err
+			}
+		}
+
 		datastoreID, _ := block[mkResourceVirtualEnvironmentVMDiskDatastoreID].(string)
 		fileFormat, _ := block[mkResourceVirtualEnvironmentVMDiskFileFormat].(string)
 		size, _ := block[mkResourceVirtualEnvironmentVMDiskSize].(int)
@@ -1834,6 +2489,42 @@ func resourceVirtualEnvironmentVMCreateCustomDisks(d *schema.ResourceData, m int
 			diskOptions += fmt.Sprintf(",mbps_wr_max=%d", speedLimitWriteBurstable)
 		}
 
+		importFrom, err := resourceVirtualEnvironmentVMGetDiskImportSource(veClient, version, fileID)
+
+		if err != nil {
+			return err
+		}
+
+		if importFrom != "" {
+			importDisk := proxmox.CustomStorageDevice{
+				Enabled:    true,
+				FileVolume: fmt.Sprintf("%s:0", datastoreID),
+				Format:     &fileFormat,
+				ImportFrom: &importFrom,
+			}
+
+			if speedLimitRead > 0 {
+				importDisk.MaxReadSpeedMbps = &speedLimitRead
+			}
+
+			if speedLimitReadBurstable > 0 {
+				importDisk.BurstableReadSpeedMbps = &speedLimitReadBurstable
+			}
+
+			if speedLimitWrite > 0 {
+				importDisk.MaxWriteSpeedMbps = &speedLimitWrite
+			}
+
+			if speedLimitWriteBurstable > 0 {
+				importDisk.BurstableWriteSpeedMbps = &speedLimitWriteBurstable
+			}
+
+			importDisks[diskInterface] = importDisk
+			importDiskSizes[diskInterface] = size
+
+			continue
+		}
+
 		fileIDParts := strings.Split(fileID, ":")
 		filePath := ""
 
@@ -1877,7 +2568,7 @@ func resourceVirtualEnvironmentVMCreateCustomDisks(d *schema.ResourceData, m int
 	}
 
 	// Execute the commands on the node and wait for the result.
-	// This is a highly experimental approach to disk imports and is not recommended by Proxmox.
+	// This is a highly experimental approach to disk imports and is only used for servers without "import-from" support.
 	if len(commands) > 0 {
 		err = veClient.ExecuteNodeCommands(nodeName, commands)
 
@@ -1886,10 +2577,130 @@ func resourceVirtualEnvironmentVMCreateCustomDisks(d *schema.ResourceData, m int
 		}
 	}
 
-	return resourceVirtualEnvironmentVMCreateStart(d, m)
+	// Import the remaining disks through the API, which allocates the next free disk names, and resize them afterwards,
+	// as the imported disks match the size of the images. The import is only limited by the timeout for creating the VM.
+	if len(importDisks) > 0 {
+		updateBody := &proxmox.VirtualEnvironmentVMUpdateRequestBody{
+			IDEDevices:       proxmox.CustomStorageDevices{},
+			SATADevices:      proxmox.CustomStorageDevices{},
+			SCSIDevices:      proxmox.CustomStorageDevices{},
+			VirtualIODevices: proxmox.CustomStorageDevices{},
+		}
+
+		for diskInterface, importDisk := range importDisks {
+			switch {
+			case strings.HasPrefix(diskInterface, "ide"):
+				updateBody.IDEDevices[diskInterface] = importDisk
+			case strings.HasPrefix(diskInterface, "sata"):
+				updateBody.SATADevices[diskInterface] = importDisk
+			case strings.HasPrefix(diskInterface, "virtio"):
+				updateBody.VirtualIODevices[diskInterface] = importDisk
+			default:
+				updateBody.SCSIDevices[diskInterface] = importDisk
+			}
+		}
+
+		task, err := veClient.UpdateVMAsync(ctx, nodeName, vmID, updateBody)
+
+		if err != nil {
+			return err
+		}
+
+		err = task.WaitContext(ctx, int(d.Timeout(schema.TimeoutCreate).Seconds()), 5)
+
+		if err != nil {
+			return err
+		}
+
+		vmConfig, err := veClient.GetVM(nodeName, vmID)
+
+		if err != nil {
+			return err
+		}
+
+		allDiskInfo := getDiskInfo(vmConfig)
+
+		for diskInterface, diskSize := range importDiskSizes {
+			importedDiskInfo := allDiskInfo[diskInterface]
+
+			if importedDiskInfo == nil {
+				return fmt.Errorf("The imported disk \"%s\" is missing from the VM configuration", diskInterface)
+			}
+
+			importedDiskSize, err := parseDiskSize(importedDiskInfo.Size)
+
+			if err != nil {
+				return err
+			}
+
+			if importedDiskSize > diskSize {
+				return fmt.Errorf("The imported disk \"%s\" (%s) is larger than the requested size (%dG)", diskInterface, *importedDiskInfo.Size, diskSize)
+			}
+
+			if importedDiskSize == diskSize {
+				continue
+			}
+
+			err = veClient.ResizeVMDisk(nodeName, vmID, &proxmox.VirtualEnvironmentVMResizeDiskRequestBody{
+				Disk: diskInterface,
+				Size: fmt.Sprintf("%dG", diskSize),
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceVirtualEnvironmentVMCreateStart(ctx, d, m)
+}
+
+// resourceVirtualEnvironmentVMGetDiskImportSource returns the source for importing a disk image through the API or an
+// empty string, if the image must be imported over SSH instead.
+func resourceVirtualEnvironmentVMGetDiskImportSource(veClient *proxmox.VirtualEnvironmentClient, version *proxmox.VirtualEnvironmentVersionResponseData, fileID string) (string, error) {
+	if !version.IsAtLeast(7, 2) {
+		return "", nil
+	}
+
+	fileIDParts := strings.SplitN(fileID, ":", 2)
+	contentType := ""
+
+	if i := strings.Index(fileIDParts[1], "/"); i >= 0 {
+		contentType = fileIDParts[1][:i]
+	}
+
+	switch contentType {
+	case "backup", "snippets", "vztmpl":
+		return "", nil
+	case "import":
+		if version.IsAtLeast(8, 2) {
+			return fileID, nil
+		}
+
+		return "", nil
+	case "iso":
+		// ISO volumes cannot be imported by their volume identifier, but root is allowed to import absolute paths.
+		if veClient.APIToken != nil || veClient.Username != proxmox.DefaultRootAccount {
+			return "", nil
+		}
+
+		datastore, err := veClient.GetDatastore(fileIDParts[0])
+
+		if err != nil {
+			return "", err
+		}
+
+		if datastore.Path == nil || *datastore.Path == "" {
+			return "", nil
+		}
+
+		return fmt.Sprintf("%s/template/%s", strings.TrimRight(*datastore.Path, "/"), fileIDParts[1]), nil
+	default:
+		return fileID, nil
+	}
 }
 
-func resourceVirtualEnvironmentVMCreateStart(d *schema.ResourceData, m interface{}) error {
+func resourceVirtualEnvironmentVMCreateStart(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	started := d.Get(mkResourceVirtualEnvironmentVMStarted).(bool)
 	template := d.Get(mkResourceVirtualEnvironmentVMTemplate).(bool)
 	reboot := d.Get(mkResourceVirtualEnvironmentVMRebootAfterCreation).(bool)
@@ -1914,7 +2725,7 @@ func resourceVirtualEnvironmentVMCreateStart(d *schema.ResourceData, m interface
 
 	// Start the virtual machine and wait for it to reach a running state before continuing.
 	startVMTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutStartVM).(int)
-	err = veClient.StartVM(nodeName, vmID, startVMTimeout)
+	err = veClient.StartVMContext(ctx, nodeName, vmID, startVMTimeout)
 
 	if err != nil {
 		return err
@@ -1923,7 +2734,7 @@ func resourceVirtualEnvironmentVMCreateStart(d *schema.ResourceData, m interface
 	if reboot {
 		rebootTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutReboot).(int)
 
-		err := veClient.RebootVM(nodeName, vmID, &proxmox.VirtualEnvironmentVMRebootRequestBody{
+		err := veClient.RebootVMContext(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentVMRebootRequestBody{
 			Timeout: &rebootTimeout,
 		}, (rebootTimeout + 30))
 
@@ -1935,6 +2746,125 @@ func resourceVirtualEnvironmentVMCreateStart(d *schema.ResourceData, m interface
 	return resourceVirtualEnvironmentVMRead(d, m)
 }
 
+func resourceVirtualEnvironmentVMCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return err
+	}
+
+	// Only the root account is allowed to configure host PCI devices, which is why we reject such changes in advance.
+	if d.HasChange(mkResourceVirtualEnvironmentVMHostPCIDevice) && veClient.Username != proxmox.DefaultRootAccount {
+		hostPCIDeviceOld, hostPCIDeviceNew := d.GetChange(mkResourceVirtualEnvironmentVMHostPCIDevice)
+
+		if len(hostPCIDeviceOld.([]interface{})) > 0 || len(hostPCIDeviceNew.([]interface{})) > 0 {
+			return fmt.Errorf("The \"%s\" block requires the \"%s\" account (current: \"%s\")", mkResourceVirtualEnvironmentVMHostPCIDevice, proxmox.DefaultRootAccount, veClient.Username)
+		}
+	}
+
+	// The NUMA nodes must add up to the CPU and memory allocation, which is why we validate them in advance.
+	clone := d.Get(mkResourceVirtualEnvironmentVMClone).([]interface{})
+	cpu := d.Get(mkResourceVirtualEnvironmentVMCPU).([]interface{})
+	memory := d.Get(mkResourceVirtualEnvironmentVMMemory).([]interface{})
+	numaDevice := d.Get(mkResourceVirtualEnvironmentVMNUMADevice).([]interface{})
+	numaKeys := []string{
+		fmt.Sprintf("%s.0.%s", mkResourceVirtualEnvironmentVMCPU, mkResourceVirtualEnvironmentVMCPUCores),
+		fmt.Sprintf("%s.0.%s", mkResourceVirtualEnvironmentVMCPU, mkResourceVirtualEnvironmentVMCPUNUMA),
+		fmt.Sprintf("%s.0.%s", mkResourceVirtualEnvironmentVMCPU, mkResourceVirtualEnvironmentVMCPUSockets),
+		fmt.Sprintf("%s.0.%s", mkResourceVirtualEnvironmentVMMemory, mkResourceVirtualEnvironmentVMMemoryDedicated),
+	}
+
+	for i := range numaDevice {
+		numaKeys = append(
+			numaKeys,
+			fmt.Sprintf("%s.%d.%s", mkResourceVirtualEnvironmentVMNUMADevice, i, mkResourceVirtualEnvironmentVMNUMADeviceCPUs),
+			fmt.Sprintf("%s.%d.%s", mkResourceVirtualEnvironmentVMNUMADevice, i, mkResourceVirtualEnvironmentVMNUMADeviceMemory),
+		)
+	}
+
+	numaKnown := true
+
+	for _, k := range numaKeys {
+		if !d.NewValueKnown(k) {
+			numaKnown = false
+		}
+	}
+
+	// The CPU and memory allocation of a cloned VM is inherited from the source VM, unless the blocks are configured.
+	if len(clone) > 0 && (len(cpu) == 0 || len(memory) == 0) {
+		numaKnown = false
+	}
+
+	if len(numaDevice) > 0 && numaKnown {
+		cpuCores := dvResourceVirtualEnvironmentVMCPUCores
+		cpuNUMA := dvResourceVirtualEnvironmentVMCPUNUMA
+		cpuSockets := dvResourceVirtualEnvironmentVMCPUSockets
+		memoryDedicated := dvResourceVirtualEnvironmentVMMemoryDedicated
+
+		if len(cpu) > 0 && cpu[0] != nil {
+			cpuBlock := cpu[0].(map[string]interface{})
+
+			cpuCores = cpuBlock[mkResourceVirtualEnvironmentVMCPUCores].(int)
+			cpuNUMA = cpuBlock[mkResourceVirtualEnvironmentVMCPUNUMA].(bool)
+			cpuSockets = cpuBlock[mkResourceVirtualEnvironmentVMCPUSockets].(int)
+		}
+
+		if len(memory) > 0 && memory[0] != nil {
+			memoryBlock := memory[0].(map[string]interface{})
+
+			memoryDedicated = memoryBlock[mkResourceVirtualEnvironmentVMMemoryDedicated].(int)
+		}
+
+		if !cpuNUMA {
+			return fmt.Errorf("The \"%s\" blocks require \"%s.%s\" to be enabled", mkResourceVirtualEnvironmentVMNUMADevice, mkResourceVirtualEnvironmentVMCPU, mkResourceVirtualEnvironmentVMCPUNUMA)
+		}
+
+		cpuCount := cpuCores * cpuSockets
+		numaCPUCount := 0
+		numaCPUIDs := map[int]bool{}
+		numaMemory := 0
+
+		for _, v := range numaDevice {
+			block := v.(map[string]interface{})
+
+			cpus, _ := block[mkResourceVirtualEnvironmentVMNUMADeviceCPUs].(string)
+			memory, _ := block[mkResourceVirtualEnvironmentVMNUMADeviceMemory].(int)
+
+			ids, err := resourceVirtualEnvironmentVMGetNUMADeviceIDs(cpus)
+
+			if err != nil {
+				return fmt.Errorf("The \"%s\" value of a \"%s\" block is invalid: %s", mkResourceVirtualEnvironmentVMNUMADeviceCPUs, mkResourceVirtualEnvironmentVMNUMADevice, err.Error())
+			}
+
+			for _, id := range ids {
+				if id >= cpuCount {
+					return fmt.Errorf("The \"%s\" blocks reference CPU %d, which exceeds the %d CPUs allocated to the VM", mkResourceVirtualEnvironmentVMNUMADevice, id, cpuCount)
+				}
+
+				if numaCPUIDs[id] {
+					return fmt.Errorf("The \"%s\" blocks assign CPU %d more than once", mkResourceVirtualEnvironmentVMNUMADevice, id)
+				}
+
+				numaCPUIDs[id] = true
+			}
+
+			numaCPUCount += len(ids)
+			numaMemory += memory
+		}
+
+		if numaCPUCount != cpuCount {
+			return fmt.Errorf("The \"%s\" blocks must cover all %d CPUs allocated to the VM (current: %d)", mkResourceVirtualEnvironmentVMNUMADevice, cpuCount, numaCPUCount)
+		}
+
+		if numaMemory != memoryDedicated {
+			return fmt.Errorf("The \"%s\" blocks must provide all %d MiB of dedicated memory allocated to the VM (current: %d)", mkResourceVirtualEnvironmentVMNUMADevice, memoryDedicated, numaMemory)
+		}
+	}
+
+	return nil
+}
+
 func resourceVirtualEnvironmentVMGetAudioDeviceList(d *schema.ResourceData, m interface{}) (proxmox.CustomAudioDevices, error) {
 	devices := d.Get(mkResourceVirtualEnvironmentVMAudioDevice).([]interface{})
 	list := make(proxmox.CustomAudioDevices, len(devices))
@@ -2159,51 +3089,248 @@ func resourceVirtualEnvironmentVMGetDiskDeviceObjects(d *schema.ResourceData, m
 			diskDeviceObjects[baseDiskInterface] = make(map[string]proxmox.CustomStorageDevice)
 		}
 
-		diskDeviceObjects[baseDiskInterface][diskInterface] = diskDevice
+		diskDeviceObjects[baseDiskInterface][diskInterface] = diskDevice
+	}
+
+	return diskDeviceObjects, nil
+}
+
+func resourceVirtualEnvironmentVMGetEFIDiskObject(d *schema.ResourceData, m interface{}) (*proxmox.CustomEFIDisk, error) {
+	efiDisk := d.Get(mkResourceVirtualEnvironmentVMEFIDisk).([]interface{})
+
+	if len(efiDisk) == 0 || efiDisk[0] == nil {
+		return nil, nil
+	}
+
+	block := efiDisk[0].(map[string]interface{})
+
+	datastoreID, _ := block[mkResourceVirtualEnvironmentVMEFIDiskDatastoreID].(string)
+	fileFormat, _ := block[mkResourceVirtualEnvironmentVMEFIDiskFileFormat].(string)
+	preEnrolledKeys := proxmox.CustomBool(block[mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys].(bool))
+	efiType, _ := block[mkResourceVirtualEnvironmentVMEFIDiskType].(string)
+
+	// The size of the new volume is ignored by the server, as it is determined by the EFI type.
+	efiDiskObject := &proxmox.CustomEFIDisk{
+		EFIType:         &efiType,
+		FileVolume:      fmt.Sprintf("%s:1", datastoreID),
+		PreEnrolledKeys: &preEnrolledKeys,
+	}
+
+	if fileFormat != "" {
+		efiDiskObject.Format = &fileFormat
+	}
+
+	return efiDiskObject, nil
+}
+
+func resourceVirtualEnvironmentVMGetEFIDiskRecreationRequired(current *proxmox.CustomEFIDisk, desired *proxmox.CustomEFIDisk) bool {
+	// Default values of "efitype" and "pre-enrolled-keys" are "2m" and "0" according to the API documentation.
+	currentPreEnrolledKeys := current.PreEnrolledKeys != nil && bool(*current.PreEnrolledKeys)
+	currentType := "2m"
+
+	if current.EFIType != nil {
+		currentType = *current.EFIType
+	}
+
+	return currentType != *desired.EFIType || currentPreEnrolledKeys != bool(*desired.PreEnrolledKeys)
+}
+
+func resourceVirtualEnvironmentVMGetEFIDiskTypeValidator() schema.SchemaValidateFunc {
+	return validation.StringInSlice([]string{
+		"2m",
+		"4m",
+	}, false)
+}
+
+func resourceVirtualEnvironmentVMGetHostPCIDeviceIDValidator() schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (s []string, es []error) {
+		v, ok := i.(string)
+
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %s to be string", k))
+			return
+		}
+
+		for _, id := range strings.Split(v, ";") {
+			if !regexp.MustCompile(`^([0-9a-fA-F]{4}:)?[0-9a-fA-F]{2}:[0-9a-fA-F]{2}(\.[0-7])?$`).MatchString(id) {
+				es = append(es, fmt.Errorf("expected %s to be a PCI device identifier like '0000:01:00.0' or a semicolon separated list of such identifiers, got %s", k, v))
+				return
+			}
+		}
+
+		return
+	}
+}
+
+func resourceVirtualEnvironmentVMGetHostPCIDeviceObjects(d *schema.ResourceData, m interface{}) (proxmox.CustomPCIDevices, error) {
+	hostPCIDevice := d.Get(mkResourceVirtualEnvironmentVMHostPCIDevice).([]interface{})
+	hostPCIDeviceObjects := make(proxmox.CustomPCIDevices, len(hostPCIDevice))
+
+	for i, hostPCIDeviceEntry := range hostPCIDevice {
+		block := hostPCIDeviceEntry.(map[string]interface{})
+
+		id, _ := block[mkResourceVirtualEnvironmentVMHostPCIDeviceID].(string)
+		mdev, _ := block[mkResourceVirtualEnvironmentVMHostPCIDeviceMDev].(string)
+		pcie := proxmox.CustomBool(block[mkResourceVirtualEnvironmentVMHostPCIDevicePCIE].(bool))
+		rombar := proxmox.CustomBool(block[mkResourceVirtualEnvironmentVMHostPCIDeviceROMBAR].(bool))
+		romFile, _ := block[mkResourceVirtualEnvironmentVMHostPCIDeviceROMFile].(string)
+		xvga := proxmox.CustomBool(block[mkResourceVirtualEnvironmentVMHostPCIDeviceXVGA].(bool))
+
+		device := proxmox.CustomPCIDevice{
+			DeviceIDs:  strings.Split(id, ";"),
+			PCIExpress: &pcie,
+			ROMBAR:     &rombar,
+			XVGA:       &xvga,
+		}
+
+		if mdev != "" {
+			device.DevicePath = &mdev
+		}
+
+		if romFile != "" {
+			device.ROMFile = &romFile
+		}
+
+		hostPCIDeviceObjects[i] = device
+	}
+
+	return hostPCIDeviceObjects, nil
+}
+
+func resourceVirtualEnvironmentVMGetNetworkDeviceObjects(d *schema.ResourceData, m interface{}) (proxmox.CustomNetworkDevices, error) {
+	networkDevice := d.Get(mkResourceVirtualEnvironmentVMNetworkDevice).([]interface{})
+	networkDeviceObjects := make(proxmox.CustomNetworkDevices, len(networkDevice))
+
+	for i, networkDeviceEntry := range networkDevice {
+		block := networkDeviceEntry.(map[string]interface{})
+
+		bridge, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceBridge].(string)
+		enabled, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceEnabled].(bool)
+		macAddress, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceMACAddress].(string)
+		model, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceModel].(string)
+		rateLimit, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceRateLimit].(float64)
+		vlanID, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceVLANID].(int)
+
+		device := proxmox.CustomNetworkDevice{
+			Enabled: enabled,
+			Model:   model,
+		}
+
+		if bridge != "" {
+			device.Bridge = &bridge
+		}
+
+		if macAddress != "" {
+			device.MACAddress = &macAddress
+		}
+
+		if rateLimit != 0 {
+			device.RateLimit = &rateLimit
+		}
+
+		if vlanID != 0 {
+			device.Tag = &vlanID
+		}
+
+		networkDeviceObjects[i] = device
+	}
+
+	return networkDeviceObjects, nil
+}
+
+func resourceVirtualEnvironmentVMGetNUMADeviceIDs(ranges string) ([]int, error) {
+	ids := []int{}
+
+	for _, r := range strings.Split(ranges, ";") {
+		bounds := strings.Split(r, "-")
+
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("Invalid range \"%s\"", r)
+		}
+
+		first, err := strconv.Atoi(bounds[0])
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid range \"%s\"", r)
+		}
+
+		last := first
+
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+
+			if err != nil || last < first {
+				return nil, fmt.Errorf("Invalid range \"%s\"", r)
+			}
+		}
+
+		for id := first; id <= last; id++ {
+			ids = append(ids, id)
+		}
 	}
 
-	return diskDeviceObjects, nil
+	return ids, nil
 }
 
-func resourceVirtualEnvironmentVMGetNetworkDeviceObjects(d *schema.ResourceData, m interface{}) (proxmox.CustomNetworkDevices, error) {
-	networkDevice := d.Get(mkResourceVirtualEnvironmentVMNetworkDevice).([]interface{})
-	networkDeviceObjects := make(proxmox.CustomNetworkDevices, len(networkDevice))
+func resourceVirtualEnvironmentVMGetNUMADeviceObjects(d *schema.ResourceData, m interface{}) (proxmox.CustomNUMADevices, error) {
+	numaDevice := d.Get(mkResourceVirtualEnvironmentVMNUMADevice).([]interface{})
+	numaDeviceObjects := make(proxmox.CustomNUMADevices, len(numaDevice))
 
-	for i, networkDeviceEntry := range networkDevice {
-		block := networkDeviceEntry.(map[string]interface{})
+	for i, numaDeviceEntry := range numaDevice {
+		block := numaDeviceEntry.(map[string]interface{})
 
-		bridge, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceBridge].(string)
-		enabled, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceEnabled].(bool)
-		macAddress, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceMACAddress].(string)
-		model, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceModel].(string)
-		rateLimit, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceRateLimit].(float64)
-		vlanID, _ := block[mkResourceVirtualEnvironmentVMNetworkDeviceVLANID].(int)
+		cpus, _ := block[mkResourceVirtualEnvironmentVMNUMADeviceCPUs].(string)
+		hostNodes, _ := block[mkResourceVirtualEnvironmentVMNUMADeviceHostNodes].(string)
+		memory := float64(block[mkResourceVirtualEnvironmentVMNUMADeviceMemory].(int))
+		policy, _ := block[mkResourceVirtualEnvironmentVMNUMADevicePolicy].(string)
 
-		device := proxmox.CustomNetworkDevice{
-			Enabled: enabled,
-			Model:   model,
+		device := proxmox.CustomNUMADevice{
+			CPUIDs: strings.Split(cpus, ";"),
+			Memory: &memory,
+			Policy: &policy,
 		}
 
-		if bridge != "" {
-			device.Bridge = &bridge
+		if hostNodes != "" {
+			hostNodeNames := strings.Split(hostNodes, ";")
+			device.HostNodeNames = &hostNodeNames
 		}
 
-		if macAddress != "" {
-			device.MACAddress = &macAddress
+		numaDeviceObjects[i] = device
+	}
+
+	return numaDeviceObjects, nil
+}
+
+func resourceVirtualEnvironmentVMGetNUMADevicePolicyValidator() schema.SchemaValidateFunc {
+	return validation.StringInSlice([]string{
+		"bind",
+		"interleave",
+		"preferred",
+	}, false)
+}
+
+func resourceVirtualEnvironmentVMGetNUMADeviceRangeValidator() schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (s []string, es []error) {
+		v, ok := i.(string)
+
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %s to be string", k))
+			return
 		}
 
-		if rateLimit != 0 {
-			device.RateLimit = &rateLimit
+		if v == "" {
+			return
 		}
 
-		if vlanID != 0 {
-			device.Tag = &vlanID
+		_, err := resourceVirtualEnvironmentVMGetNUMADeviceIDs(v)
+
+		if err != nil {
+			es = append(es, fmt.Errorf("expected %s to be a range like '0-3' or a semicolon separated list of such ranges, got %s", k, v))
+			return
 		}
 
-		networkDeviceObjects[i] = device
+		return
 	}
-
-	return networkDeviceObjects, nil
 }
 
 func resourceVirtualEnvironmentVMGetOperatingSystemTypeValidator() schema.SchemaValidateFunc {
@@ -2223,6 +3350,34 @@ func resourceVirtualEnvironmentVMGetOperatingSystemTypeValidator() schema.Schema
 	}, false)
 }
 
+func resourceVirtualEnvironmentVMGetRNGDeviceObject(d *schema.ResourceData, m interface{}) (*proxmox.CustomRNGDevice, error) {
+	rngDevice := d.Get(mkResourceVirtualEnvironmentVMRNGDevice).([]interface{})
+
+	if len(rngDevice) == 0 || rngDevice[0] == nil {
+		return nil, nil
+	}
+
+	block := rngDevice[0].(map[string]interface{})
+
+	maxBytes, _ := block[mkResourceVirtualEnvironmentVMRNGDeviceMaxBytes].(int)
+	period, _ := block[mkResourceVirtualEnvironmentVMRNGDevicePeriod].(int)
+	source, _ := block[mkResourceVirtualEnvironmentVMRNGDeviceSource].(string)
+
+	return &proxmox.CustomRNGDevice{
+		MaxBytes: &maxBytes,
+		Period:   &period,
+		Source:   source,
+	}, nil
+}
+
+func resourceVirtualEnvironmentVMGetRNGDeviceSourceValidator() schema.SchemaValidateFunc {
+	return validation.StringInSlice([]string{
+		"/dev/hwrng",
+		"/dev/random",
+		"/dev/urandom",
+	}, false)
+}
+
 func resourceVirtualEnvironmentVMGetSerialDeviceList(d *schema.ResourceData, m interface{}) (proxmox.CustomSerialDevices, error) {
 	device := d.Get(mkResourceVirtualEnvironmentVMSerialDevice).([]interface{})
 	list := make(proxmox.CustomSerialDevices, len(device))
@@ -2256,6 +3411,122 @@ func resourceVirtualEnvironmentVMGetSerialDeviceValidator() schema.SchemaValidat
 	}
 }
 
+func resourceVirtualEnvironmentVMGetSMBIOSObject(d *schema.ResourceData, m interface{}) (*proxmox.CustomSMBIOS, error) {
+	smbios := d.Get(mkResourceVirtualEnvironmentVMSMBIOS).([]interface{})
+
+	if len(smbios) == 0 || smbios[0] == nil {
+		return nil, nil
+	}
+
+	block := smbios[0].(map[string]interface{})
+
+	// The values are encoded with base64, as they may contain characters which are not permitted by the API.
+	encodeValue := func(k string) *string {
+		v, _ := block[k].(string)
+
+		if v == "" {
+			return nil
+		}
+
+		encodedValue := base64.StdEncoding.EncodeToString([]byte(v))
+
+		return &encodedValue
+	}
+
+	smbiosBase64 := proxmox.CustomBool(true)
+	smbiosObject := &proxmox.CustomSMBIOS{
+		Base64:       &smbiosBase64,
+		Family:       encodeValue(mkResourceVirtualEnvironmentVMSMBIOSFamily),
+		Manufacturer: encodeValue(mkResourceVirtualEnvironmentVMSMBIOSManufacturer),
+		Product:      encodeValue(mkResourceVirtualEnvironmentVMSMBIOSProduct),
+		Serial:       encodeValue(mkResourceVirtualEnvironmentVMSMBIOSSerial),
+		SKU:          encodeValue(mkResourceVirtualEnvironmentVMSMBIOSSKU),
+		Version:      encodeValue(mkResourceVirtualEnvironmentVMSMBIOSVersion),
+	}
+
+	uuid, _ := block[mkResourceVirtualEnvironmentVMSMBIOSUUID].(string)
+
+	if uuid != "" {
+		smbiosObject.UUID = &uuid
+	}
+
+	return smbiosObject, nil
+}
+
+func resourceVirtualEnvironmentVMGetTPMStateObject(d *schema.ResourceData, m interface{}) (*proxmox.CustomTPMState, error) {
+	tpmState := d.Get(mkResourceVirtualEnvironmentVMTPMState).([]interface{})
+
+	if len(tpmState) == 0 || tpmState[0] == nil {
+		return nil, nil
+	}
+
+	block := tpmState[0].(map[string]interface{})
+
+	datastoreID, _ := block[mkResourceVirtualEnvironmentVMTPMStateDatastoreID].(string)
+	version, _ := block[mkResourceVirtualEnvironmentVMTPMStateVersion].(string)
+
+	// The size of the new volume is ignored by the server, as it is determined by the TPM version.
+	return &proxmox.CustomTPMState{
+		FileVolume: fmt.Sprintf("%s:1", datastoreID),
+		Version:    &version,
+	}, nil
+}
+
+func resourceVirtualEnvironmentVMGetTPMStateRecreationRequired(current *proxmox.CustomTPMState, desired *proxmox.CustomTPMState) bool {
+	// Default value of "version" is "v1.2" according to the API documentation.
+	currentVersion := "v1.2"
+
+	if current.Version != nil {
+		currentVersion = *current.Version
+	}
+
+	return currentVersion != *desired.Version
+}
+
+func resourceVirtualEnvironmentVMGetTPMStateVersionValidator() schema.SchemaValidateFunc {
+	return validation.StringInSlice([]string{
+		"v1.2",
+		"v2.0",
+	}, false)
+}
+
+func resourceVirtualEnvironmentVMGetUSBDeviceHostValidator() schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (s []string, es []error) {
+		v, ok := i.(string)
+
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %s to be string", k))
+			return
+		}
+
+		if v != "spice" && !regexp.MustCompile(`^([0-9a-fA-F]{4}:[0-9a-fA-F]{4}|[0-9]+-[0-9]+(\.[0-9]+)*)$`).MatchString(v) {
+			es = append(es, fmt.Errorf("expected %s to be a vendor and product identifier like '046d:c52b', a bus and port like '1-2.3' or 'spice', got %s", k, v))
+			return
+		}
+
+		return
+	}
+}
+
+func resourceVirtualEnvironmentVMGetUSBDeviceObjects(d *schema.ResourceData, m interface{}) (proxmox.CustomUSBDevices, error) {
+	usbDevice := d.Get(mkResourceVirtualEnvironmentVMUSBDevice).([]interface{})
+	usbDeviceObjects := make(proxmox.CustomUSBDevices, len(usbDevice))
+
+	for i, usbDeviceEntry := range usbDevice {
+		block := usbDeviceEntry.(map[string]interface{})
+
+		host, _ := block[mkResourceVirtualEnvironmentVMUSBDeviceHost].(string)
+		usb3 := proxmox.CustomBool(block[mkResourceVirtualEnvironmentVMUSBDeviceUSB3].(bool))
+
+		usbDeviceObjects[i] = proxmox.CustomUSBDevice{
+			HostDevice: host,
+			USB3:       &usb3,
+		}
+	}
+
+	return usbDeviceObjects, nil
+}
+
 func resourceVirtualEnvironmentVMGetVGADeviceObject(d *schema.ResourceData, m interface{}) (*proxmox.CustomVGADevice, error) {
 	resource := resourceVirtualEnvironmentVM()
 
@@ -2288,6 +3559,88 @@ func resourceVirtualEnvironmentVMGetVGADeviceObject(d *schema.ResourceData, m in
 	return vgaDevice, nil
 }
 
+func resourceVirtualEnvironmentVMGetVolumeDatastoreID(fileVolume string) string {
+	return strings.SplitN(fileVolume, ":", 2)[0]
+}
+
+func resourceVirtualEnvironmentVMGetWatchdogDeviceActionValidator() schema.SchemaValidateFunc {
+	return validation.StringInSlice([]string{
+		"debug",
+		"none",
+		"pause",
+		"poweroff",
+		"reset",
+		"shutdown",
+	}, false)
+}
+
+func resourceVirtualEnvironmentVMGetWatchdogDeviceModelValidator() schema.SchemaValidateFunc {
+	return validation.StringInSlice([]string{
+		"i6300esb",
+		"ib700",
+	}, false)
+}
+
+func resourceVirtualEnvironmentVMGetWatchdogDeviceObject(d *schema.ResourceData, m interface{}) (*proxmox.CustomWatchdogDevice, error) {
+	watchdogDevice := d.Get(mkResourceVirtualEnvironmentVMWatchdogDevice).([]interface{})
+
+	if len(watchdogDevice) == 0 || watchdogDevice[0] == nil {
+		return nil, nil
+	}
+
+	block := watchdogDevice[0].(map[string]interface{})
+
+	action, _ := block[mkResourceVirtualEnvironmentVMWatchdogDeviceAction].(string)
+	model, _ := block[mkResourceVirtualEnvironmentVMWatchdogDeviceModel].(string)
+
+	return &proxmox.CustomWatchdogDevice{
+		Action: &action,
+		Model:  model,
+	}, nil
+}
+
+func resourceVirtualEnvironmentVMImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodeName, vmID, err := parseNodeResourceID(d.Id())
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = veClient.GetVM(nodeName, vmID)
+
+	if err != nil {
+		if proxmox.IsNotFound(err) {
+			return nil, fmt.Errorf("VM \"%d\" does not exist on node \"%s\"", vmID, nodeName)
+		}
+
+		return nil, err
+	}
+
+	d.SetId(strconv.Itoa(vmID))
+
+	d.Set(mkResourceVirtualEnvironmentVMNodeName, nodeName)
+	d.Set(mkResourceVirtualEnvironmentVMVMID, vmID)
+
+	// The following arguments are not available through the API, which is why they are set to their default values.
+	d.Set(mkResourceVirtualEnvironmentVMRebootAfterCreation, dvResourceVirtualEnvironmentVMRebootAfterCreation)
+	d.Set(mkResourceVirtualEnvironmentVMTimeoutClone, dvResourceVirtualEnvironmentVMTimeoutClone)
+	d.Set(mkResourceVirtualEnvironmentVMTimeoutMigrate, dvResourceVirtualEnvironmentVMTimeoutMigrate)
+	d.Set(mkResourceVirtualEnvironmentVMTimeoutMoveDisk, dvResourceVirtualEnvironmentVMTimeoutMoveDisk)
+	d.Set(mkResourceVirtualEnvironmentVMTimeoutReboot, dvResourceVirtualEnvironmentVMTimeoutReboot)
+	d.Set(mkResourceVirtualEnvironmentVMTimeoutShutdownVM, dvResourceVirtualEnvironmentVMTimeoutShutdownVM)
+	d.Set(mkResourceVirtualEnvironmentVMTimeoutStartVM, dvResourceVirtualEnvironmentVMTimeoutStartVM)
+	d.Set(mkResourceVirtualEnvironmentVMTimeoutStopVM, dvResourceVirtualEnvironmentVMTimeoutStopVM)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceVirtualEnvironmentVMRead(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
@@ -2307,8 +3660,7 @@ func resourceVirtualEnvironmentVMRead(d *schema.ResourceData, m interface{}) err
 	vmConfig, err := veClient.GetVM(nodeName, vmID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") ||
-			(strings.Contains(err.Error(), "HTTP 500") && strings.Contains(err.Error(), "does not exist")) {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -2498,6 +3850,12 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 		cpu[mkResourceVirtualEnvironmentVMCPUHotplugged] = 0
 	}
 
+	if vmConfig.NUMAEnabled != nil {
+		cpu[mkResourceVirtualEnvironmentVMCPUNUMA] = bool(*vmConfig.NUMAEnabled)
+	} else {
+		cpu[mkResourceVirtualEnvironmentVMCPUNUMA] = false
+	}
+
 	if vmConfig.CPUSockets != nil {
 		cpu[mkResourceVirtualEnvironmentVMCPUSockets] = *vmConfig.CPUSockets
 	} else {
@@ -2543,12 +3901,25 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 		cpu[mkResourceVirtualEnvironmentVMCPUCores] != dvResourceVirtualEnvironmentVMCPUCores ||
 		len(cpu[mkResourceVirtualEnvironmentVMCPUFlags].([]interface{})) > 0 ||
 		cpu[mkResourceVirtualEnvironmentVMCPUHotplugged] != dvResourceVirtualEnvironmentVMCPUHotplugged ||
+		cpu[mkResourceVirtualEnvironmentVMCPUNUMA] != dvResourceVirtualEnvironmentVMCPUNUMA ||
 		cpu[mkResourceVirtualEnvironmentVMCPUSockets] != dvResourceVirtualEnvironmentVMCPUSockets ||
 		cpu[mkResourceVirtualEnvironmentVMCPUType] != dvResourceVirtualEnvironmentVMCPUType ||
 		cpu[mkResourceVirtualEnvironmentVMCPUUnits] != dvResourceVirtualEnvironmentVMCPUUnits {
 		d.Set(mkResourceVirtualEnvironmentVMCPU, []interface{}{cpu})
 	}
 
+	// The file identifiers of imported disk images are not available through the API and are kept from the state.
+	currentDiskFileIDs := map[string]interface{}{}
+
+	for _, cd := range d.Get(mkResourceVirtualEnvironmentVMDisk).([]interface{}) {
+		if cd == nil {
+			continue
+		}
+
+		currentDisk := cd.(map[string]interface{})
+		currentDiskFileIDs[currentDisk[mkResourcevirtualEnvironmentVMDiskInterface].(string)] = currentDisk[mkResourceVirtualEnvironmentVMDiskFileID]
+	}
+
 	diskMap := map[string]interface{}{}
 	orderedDiskList := []interface{}{}
 	diskObjects := getDiskInfo(vmConfig)
@@ -2564,11 +3935,16 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 
 		disk[mkResourceVirtualEnvironmentVMDiskDatastoreID] = fileIDParts[0]
 
-		disk[mkResourceVirtualEnvironmentVMDiskFileID] = dd.FileID
+		if currentFileID, ok := currentDiskFileIDs[di]; ok {
+			disk[mkResourceVirtualEnvironmentVMDiskFileID] = currentFileID
+		} else {
+			disk[mkResourceVirtualEnvironmentVMDiskFileID] = dvResourceVirtualEnvironmentVMDiskFileID
+		}
+
 		if dd.Format == nil {
 			disk[mkResourceVirtualEnvironmentVMDiskFileFormat] = "qcow2"
 		} else {
-			disk[mkResourceVirtualEnvironmentVMDiskFileFormat] = dd.Format
+			disk[mkResourceVirtualEnvironmentVMDiskFileFormat] = *dd.Format
 		}
 		disk[mkResourcevirtualEnvironmentVMDiskInterface] = di
 
@@ -2637,6 +4013,118 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 		d.Set(mkResourceVirtualEnvironmentVMDisk, orderedDiskList)
 	}
 
+	// Compare the EFI disk to the one stored in the state.
+	efiDiskList := []interface{}{}
+
+	if vmConfig.EFIDisk != nil {
+		efiDisk := map[string]interface{}{}
+
+		efiDisk[mkResourceVirtualEnvironmentVMEFIDiskDatastoreID] = resourceVirtualEnvironmentVMGetVolumeDatastoreID(vmConfig.EFIDisk.FileVolume)
+
+		if vmConfig.EFIDisk.Format != nil {
+			efiDisk[mkResourceVirtualEnvironmentVMEFIDiskFileFormat] = *vmConfig.EFIDisk.Format
+		} else if strings.HasSuffix(vmConfig.EFIDisk.FileVolume, ".qcow2") {
+			efiDisk[mkResourceVirtualEnvironmentVMEFIDiskFileFormat] = "qcow2"
+		} else if strings.HasSuffix(vmConfig.EFIDisk.FileVolume, ".vmdk") {
+			efiDisk[mkResourceVirtualEnvironmentVMEFIDiskFileFormat] = "vmdk"
+		} else {
+			efiDisk[mkResourceVirtualEnvironmentVMEFIDiskFileFormat] = "raw"
+		}
+
+		if vmConfig.EFIDisk.PreEnrolledKeys != nil {
+			efiDisk[mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys] = bool(*vmConfig.EFIDisk.PreEnrolledKeys)
+		} else {
+			efiDisk[mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys] = false
+		}
+
+		if vmConfig.EFIDisk.EFIType != nil {
+			efiDisk[mkResourceVirtualEnvironmentVMEFIDiskType] = *vmConfig.EFIDisk.EFIType
+		} else {
+			// Default value of "efitype" is "2m" according to the API documentation.
+			efiDisk[mkResourceVirtualEnvironmentVMEFIDiskType] = "2m"
+		}
+
+		efiDiskList = []interface{}{efiDisk}
+	}
+
+	currentEFIDisk := d.Get(mkResourceVirtualEnvironmentVMEFIDisk).([]interface{})
+
+	if len(clone) == 0 || len(currentEFIDisk) > 0 {
+		d.Set(mkResourceVirtualEnvironmentVMEFIDisk, efiDiskList)
+	}
+
+	// Compare the host PCI devices to those stored in the state.
+	hostPCIDevices := make([]interface{}, maxResourceVirtualEnvironmentVMHostPCIDevices)
+	hostPCIDevicesArray := []*proxmox.CustomPCIDevice{
+		vmConfig.PCIDevice0,
+		vmConfig.PCIDevice1,
+		vmConfig.PCIDevice2,
+		vmConfig.PCIDevice3,
+		vmConfig.PCIDevice4,
+		vmConfig.PCIDevice5,
+		vmConfig.PCIDevice6,
+		vmConfig.PCIDevice7,
+		vmConfig.PCIDevice8,
+		vmConfig.PCIDevice9,
+		vmConfig.PCIDevice10,
+		vmConfig.PCIDevice11,
+		vmConfig.PCIDevice12,
+		vmConfig.PCIDevice13,
+		vmConfig.PCIDevice14,
+		vmConfig.PCIDevice15,
+	}
+	hostPCIDevicesCount := 0
+
+	for _, pd := range hostPCIDevicesArray {
+		if pd == nil {
+			continue
+		}
+
+		hostPCIDevice := map[string]interface{}{}
+
+		hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceID] = strings.Join(pd.DeviceIDs, ";")
+
+		if pd.DevicePath != nil {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceMDev] = *pd.DevicePath
+		} else {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceMDev] = ""
+		}
+
+		if pd.PCIExpress != nil {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDevicePCIE] = bool(*pd.PCIExpress)
+		} else {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDevicePCIE] = false
+		}
+
+		if pd.ROMBAR != nil {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceROMBAR] = bool(*pd.ROMBAR)
+		} else {
+			// Default value of "rombar" is "1" according to the API documentation.
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceROMBAR] = true
+		}
+
+		if pd.ROMFile != nil {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceROMFile] = *pd.ROMFile
+		} else {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceROMFile] = ""
+		}
+
+		if pd.XVGA != nil {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceXVGA] = bool(*pd.XVGA)
+		} else {
+			hostPCIDevice[mkResourceVirtualEnvironmentVMHostPCIDeviceXVGA] = false
+		}
+
+		hostPCIDevices[hostPCIDevicesCount] = hostPCIDevice
+		hostPCIDevicesCount++
+	}
+
+	currentHostPCIDevice := d.Get(mkResourceVirtualEnvironmentVMHostPCIDevice).([]interface{})
+
+	if len(clone) == 0 || len(currentHostPCIDevice) > 0 {
+		d.Set(mkResourceVirtualEnvironmentVMHostPCIDevice, hostPCIDevices[:hostPCIDevicesCount])
+	}
+
 	// Compare the initialization configuration to the one stored in the state.
 	initialization := map[string]interface{}{}
 
@@ -2709,16 +4197,16 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 			if ipConfig.GatewayIPv6 != nil || ipConfig.IPv6 != nil {
 				ipv6 := map[string]interface{}{}
 
-				if ipConfig.IPv4 != nil {
-					ipv6[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv4Address] = *ipConfig.IPv6
+				if ipConfig.IPv6 != nil {
+					ipv6[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv6Address] = *ipConfig.IPv6
 				} else {
-					ipv6[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv4Address] = ""
+					ipv6[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv6Address] = ""
 				}
 
-				if ipConfig.GatewayIPv4 != nil {
-					ipv6[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv4Gateway] = *ipConfig.GatewayIPv6
+				if ipConfig.GatewayIPv6 != nil {
+					ipv6[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv6Gateway] = *ipConfig.GatewayIPv6
 				} else {
-					ipv6[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv4Gateway] = ""
+					ipv6[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv6Gateway] = ""
 				}
 
 				ipConfigItem[mkResourceVirtualEnvironmentVMInitializationIPConfigIPv6] = []interface{}{ipv6}
@@ -2892,13 +4380,64 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 			d.Set(mkResourceVirtualEnvironmentVMNetworkDevice, networkDeviceList[:networkDeviceLast+1])
 		}
 	} else {
-		d.Set(mkResourceVirtualEnvironmentVMMACAddresses, macAddresses[0:len(currentNetworkDeviceList)])
+		d.Set(mkResourceVirtualEnvironmentVMMACAddresses, macAddresses[0:networkDeviceLast+1])
 
 		if len(currentNetworkDeviceList) > 0 || networkDeviceLast > -1 {
 			d.Set(mkResourceVirtualEnvironmentVMNetworkDevice, networkDeviceList[:networkDeviceLast+1])
 		}
 	}
 
+	// Compare the NUMA nodes to those stored in the state.
+	numaDevices := make([]interface{}, maxResourceVirtualEnvironmentVMNUMADevices)
+	numaDevicesArray := []*proxmox.CustomNUMADevice{
+		vmConfig.NUMADevice0,
+		vmConfig.NUMADevice1,
+		vmConfig.NUMADevice2,
+		vmConfig.NUMADevice3,
+		vmConfig.NUMADevice4,
+		vmConfig.NUMADevice5,
+		vmConfig.NUMADevice6,
+		vmConfig.NUMADevice7,
+	}
+	numaDevicesCount := 0
+
+	for _, nd := range numaDevicesArray {
+		if nd == nil {
+			continue
+		}
+
+		numaDevice := map[string]interface{}{}
+
+		numaDevice[mkResourceVirtualEnvironmentVMNUMADeviceCPUs] = strings.Join(nd.CPUIDs, ";")
+
+		if nd.HostNodeNames != nil {
+			numaDevice[mkResourceVirtualEnvironmentVMNUMADeviceHostNodes] = strings.Join(*nd.HostNodeNames, ";")
+		} else {
+			numaDevice[mkResourceVirtualEnvironmentVMNUMADeviceHostNodes] = ""
+		}
+
+		if nd.Memory != nil {
+			numaDevice[mkResourceVirtualEnvironmentVMNUMADeviceMemory] = int(*nd.Memory)
+		} else {
+			numaDevice[mkResourceVirtualEnvironmentVMNUMADeviceMemory] = 0
+		}
+
+		if nd.Policy != nil {
+			numaDevice[mkResourceVirtualEnvironmentVMNUMADevicePolicy] = *nd.Policy
+		} else {
+			numaDevice[mkResourceVirtualEnvironmentVMNUMADevicePolicy] = ""
+		}
+
+		numaDevices[numaDevicesCount] = numaDevice
+		numaDevicesCount++
+	}
+
+	currentNUMADevice := d.Get(mkResourceVirtualEnvironmentVMNUMADevice).([]interface{})
+
+	if len(clone) == 0 || len(currentNUMADevice) > 0 {
+		d.Set(mkResourceVirtualEnvironmentVMNUMADevice, numaDevices[:numaDevicesCount])
+	}
+
 	// Compare the operating system configuration to the one stored in the state.
 	operatingSystem := map[string]interface{}{}
 
@@ -2921,13 +4460,44 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 		d.Set(mkResourceVirtualEnvironmentVMOperatingSystem, []interface{}{})
 	}
 
-	// Compare the pool ID to the value stored in the state.
-	currentPoolID := d.Get(mkResourceVirtualEnvironmentVMPoolID).(string)
+	// Compare the pool ID to the value stored in the state.
+	currentPoolID := d.Get(mkResourceVirtualEnvironmentVMPoolID).(string)
+
+	if len(clone) == 0 || currentPoolID != dvResourceVirtualEnvironmentVMPoolID {
+		if vmConfig.PoolID != nil {
+			d.Set(mkResourceVirtualEnvironmentVMPoolID, *vmConfig.PoolID)
+		}
+	}
+
+	// Compare the RNG device to the one stored in the state.
+	rngDevices := []interface{}{}
+
+	if vmConfig.RNGDevice != nil {
+		rngDevice := map[string]interface{}{}
+
+		if vmConfig.RNGDevice.MaxBytes != nil {
+			rngDevice[mkResourceVirtualEnvironmentVMRNGDeviceMaxBytes] = *vmConfig.RNGDevice.MaxBytes
+		} else {
+			// Default value of "max_bytes" is "1024" according to the API documentation.
+			rngDevice[mkResourceVirtualEnvironmentVMRNGDeviceMaxBytes] = 1024
+		}
+
+		if vmConfig.RNGDevice.Period != nil {
+			rngDevice[mkResourceVirtualEnvironmentVMRNGDevicePeriod] = *vmConfig.RNGDevice.Period
+		} else {
+			// Default value of "period" is "1000" according to the API documentation.
+			rngDevice[mkResourceVirtualEnvironmentVMRNGDevicePeriod] = 1000
+		}
+
+		rngDevice[mkResourceVirtualEnvironmentVMRNGDeviceSource] = vmConfig.RNGDevice.Source
+
+		rngDevices = append(rngDevices, rngDevice)
+	}
+
+	currentRNGDevice := d.Get(mkResourceVirtualEnvironmentVMRNGDevice).([]interface{})
 
-	if len(clone) == 0 || currentPoolID != dvResourceVirtualEnvironmentVMPoolID {
-		if vmConfig.PoolID != nil {
-			d.Set(mkResourceVirtualEnvironmentVMPoolID, *vmConfig.PoolID)
-		}
+	if len(clone) == 0 || len(currentRNGDevice) > 0 {
+		d.Set(mkResourceVirtualEnvironmentVMRNGDevice, rngDevices)
 	}
 
 	// Compare the serial devices to those stored in the state.
@@ -2959,6 +4529,114 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 		d.Set(mkResourceVirtualEnvironmentVMSerialDevice, serialDevices[:serialDevicesCount])
 	}
 
+	// Compare the SMBIOS settings to those stored in the state.
+	smbiosList := []interface{}{}
+
+	if vmConfig.SMBIOS != nil {
+		smbios := map[string]interface{}{}
+		smbiosValues := map[string]*string{
+			mkResourceVirtualEnvironmentVMSMBIOSFamily:       vmConfig.SMBIOS.Family,
+			mkResourceVirtualEnvironmentVMSMBIOSManufacturer: vmConfig.SMBIOS.Manufacturer,
+			mkResourceVirtualEnvironmentVMSMBIOSProduct:      vmConfig.SMBIOS.Product,
+			mkResourceVirtualEnvironmentVMSMBIOSSerial:       vmConfig.SMBIOS.Serial,
+			mkResourceVirtualEnvironmentVMSMBIOSSKU:          vmConfig.SMBIOS.SKU,
+			mkResourceVirtualEnvironmentVMSMBIOSVersion:      vmConfig.SMBIOS.Version,
+		}
+
+		for k, v := range smbiosValues {
+			if v == nil {
+				smbios[k] = ""
+				continue
+			}
+
+			if vmConfig.SMBIOS.Base64 != nil && *vmConfig.SMBIOS.Base64 {
+				decodedValue, err := base64.StdEncoding.DecodeString(*v)
+
+				if err != nil {
+					return err
+				}
+
+				smbios[k] = string(decodedValue)
+			} else {
+				smbios[k] = *v
+			}
+		}
+
+		if vmConfig.SMBIOS.UUID != nil {
+			smbios[mkResourceVirtualEnvironmentVMSMBIOSUUID] = *vmConfig.SMBIOS.UUID
+		} else {
+			smbios[mkResourceVirtualEnvironmentVMSMBIOSUUID] = ""
+		}
+
+		smbiosList = []interface{}{smbios}
+	}
+
+	currentSMBIOS := d.Get(mkResourceVirtualEnvironmentVMSMBIOS).([]interface{})
+
+	if len(clone) == 0 || len(currentSMBIOS) > 0 {
+		d.Set(mkResourceVirtualEnvironmentVMSMBIOS, smbiosList)
+	}
+
+	// Compare the TPM state to the one stored in the state.
+	tpmStateList := []interface{}{}
+
+	if vmConfig.TPMState != nil {
+		tpmState := map[string]interface{}{}
+
+		tpmState[mkResourceVirtualEnvironmentVMTPMStateDatastoreID] = resourceVirtualEnvironmentVMGetVolumeDatastoreID(vmConfig.TPMState.FileVolume)
+
+		if vmConfig.TPMState.Version != nil {
+			tpmState[mkResourceVirtualEnvironmentVMTPMStateVersion] = *vmConfig.TPMState.Version
+		} else {
+			// Default value of "version" is "v1.2" according to the API documentation.
+			tpmState[mkResourceVirtualEnvironmentVMTPMStateVersion] = "v1.2"
+		}
+
+		tpmStateList = []interface{}{tpmState}
+	}
+
+	currentTPMState := d.Get(mkResourceVirtualEnvironmentVMTPMState).([]interface{})
+
+	if len(clone) == 0 || len(currentTPMState) > 0 {
+		d.Set(mkResourceVirtualEnvironmentVMTPMState, tpmStateList)
+	}
+
+	// Compare the USB devices to those stored in the state.
+	usbDevices := make([]interface{}, maxResourceVirtualEnvironmentVMUSBDevices)
+	usbDevicesArray := []*proxmox.CustomUSBDevice{
+		vmConfig.USBDevice0,
+		vmConfig.USBDevice1,
+		vmConfig.USBDevice2,
+		vmConfig.USBDevice3,
+		vmConfig.USBDevice4,
+	}
+	usbDevicesCount := 0
+
+	for _, ud := range usbDevicesArray {
+		if ud == nil {
+			continue
+		}
+
+		usbDevice := map[string]interface{}{}
+
+		usbDevice[mkResourceVirtualEnvironmentVMUSBDeviceHost] = ud.HostDevice
+
+		if ud.USB3 != nil {
+			usbDevice[mkResourceVirtualEnvironmentVMUSBDeviceUSB3] = bool(*ud.USB3)
+		} else {
+			usbDevice[mkResourceVirtualEnvironmentVMUSBDeviceUSB3] = false
+		}
+
+		usbDevices[usbDevicesCount] = usbDevice
+		usbDevicesCount++
+	}
+
+	currentUSBDevice := d.Get(mkResourceVirtualEnvironmentVMUSBDevice).([]interface{})
+
+	if len(clone) == 0 || len(currentUSBDevice) > 0 {
+		d.Set(mkResourceVirtualEnvironmentVMUSBDevice, usbDevices[:usbDevicesCount])
+	}
+
 	// Compare the VGA configuration to the one stored in the state.
 	vga := map[string]interface{}{}
 
@@ -3005,11 +4683,36 @@ func resourceVirtualEnvironmentVMReadCustom(d *schema.ResourceData, m interface{
 		d.Set(mkResourceVirtualEnvironmentVMVGA, []interface{}{})
 	}
 
+	// Compare the watchdog device to the one stored in the state.
+	watchdogDevices := []interface{}{}
+
+	if vmConfig.WatchdogDevice != nil {
+		watchdogDevice := map[string]interface{}{}
+
+		if vmConfig.WatchdogDevice.Action != nil {
+			watchdogDevice[mkResourceVirtualEnvironmentVMWatchdogDeviceAction] = *vmConfig.WatchdogDevice.Action
+		} else {
+			// Default action is "reset" according to the QEMU documentation.
+			watchdogDevice[mkResourceVirtualEnvironmentVMWatchdogDeviceAction] = "reset"
+		}
+
+		watchdogDevice[mkResourceVirtualEnvironmentVMWatchdogDeviceModel] = vmConfig.WatchdogDevice.Model
+
+		watchdogDevices = append(watchdogDevices, watchdogDevice)
+	}
+
+	currentWatchdogDevice := d.Get(mkResourceVirtualEnvironmentVMWatchdogDevice).([]interface{})
+
+	if len(clone) == 0 || len(currentWatchdogDevice) > 0 {
+		d.Set(mkResourceVirtualEnvironmentVMWatchdogDevice, watchdogDevices)
+	}
+
 	return resourceVirtualEnvironmentVMReadNetworkValues(d, m, vmID, vmConfig)
 }
 
 func resourceVirtualEnvironmentVMReadNetworkValues(d *schema.ResourceData, m interface{}, vmID int, vmConfig *proxmox.VirtualEnvironmentVMGetResponseData) error {
 	config := m.(providerConfiguration)
+	ctx := config.GetStopContext()
 	veClient, err := config.GetVEClient()
 
 	if err != nil {
@@ -3039,7 +4742,7 @@ func resourceVirtualEnvironmentVMReadNetworkValues(d *schema.ResourceData, m int
 			}
 
 			macAddresses := []interface{}{}
-			networkInterfaces, err := veClient.WaitForNetworkInterfacesFromVMAgent(nodeName, vmID, int(agentTimeout.Seconds()), 5, true)
+			networkInterfaces, err := veClient.WaitForNetworkInterfacesFromVMAgentContext(ctx, nodeName, vmID, int(agentTimeout.Seconds()), 5, true)
 
 			if err == nil && networkInterfaces.Result != nil {
 				ipv4Addresses = make([]interface{}, len(*networkInterfaces.Result))
@@ -3137,6 +4840,17 @@ func resourceVirtualEnvironmentVMReadPrimitiveValues(d *schema.ResourceData, m i
 		}
 	}
 
+	currentOnBoot := d.Get(mkResourceVirtualEnvironmentVMOnBoot).(bool)
+
+	if len(clone) == 0 || currentOnBoot != dvResourceVirtualEnvironmentVMOnBoot {
+		if vmConfig.StartOnBoot != nil {
+			d.Set(mkResourceVirtualEnvironmentVMOnBoot, bool(*vmConfig.StartOnBoot))
+		} else {
+			// Default value of "onboot" is "0" according to the API documentation.
+			d.Set(mkResourceVirtualEnvironmentVMOnBoot, false)
+		}
+	}
+
 	if d.Get(mkResourceVirtualEnvironmentVMTemplate).(bool) != true {
 		d.Set(mkResourceVirtualEnvironmentVMStarted, vmStatus.Status == "running")
 	}
@@ -3166,8 +4880,90 @@ func resourceVirtualEnvironmentVMReadPrimitiveValues(d *schema.ResourceData, m i
 	return nil
 }
 
+func resourceVirtualEnvironmentVMMigrate(ctx context.Context, d *schema.ResourceData, m interface{}, sourceNodeName string, targetNodeName string, vmID int) error {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return err
+	}
+
+	status, err := veClient.GetVMStatus(sourceNodeName, vmID)
+
+	if err != nil {
+		return err
+	}
+
+	// Running VMs are migrated online, while all other VMs are migrated offline.
+	onlineMigration := proxmox.CustomBool(status.Status == "running")
+
+	migrationBody := &proxmox.VirtualEnvironmentVMMigrateRequestBody{
+		OnlineMigration: &onlineMigration,
+		TargetNode:      targetNodeName,
+	}
+
+	migration := d.Get(mkResourceVirtualEnvironmentVMMigration).([]interface{})
+
+	if len(migration) > 0 && migration[0] != nil {
+		migrationBlock := migration[0].(map[string]interface{})
+
+		datastoreMapping := migrationBlock[mkResourceVirtualEnvironmentVMMigrationDatastoreMapping].(map[string]interface{})
+		targetDatastoreID := migrationBlock[mkResourceVirtualEnvironmentVMMigrationTargetDatastoreID].(string)
+		withLocalDisks := proxmox.CustomBool(migrationBlock[mkResourceVirtualEnvironmentVMMigrationWithLocalDisks].(bool))
+
+		// The target storage is either a single datastore or a list of source:target pairs, which may end with a fallback datastore.
+		targetStorage := []string{}
+		sourceDatastoreIDs := []string{}
+
+		for k := range datastoreMapping {
+			sourceDatastoreIDs = append(sourceDatastoreIDs, k)
+		}
+
+		sort.Strings(sourceDatastoreIDs)
+
+		for _, k := range sourceDatastoreIDs {
+			targetStorage = append(targetStorage, fmt.Sprintf("%s:%s", k, datastoreMapping[k].(string)))
+		}
+
+		if targetDatastoreID != "" {
+			targetStorage = append(targetStorage, targetDatastoreID)
+		}
+
+		if len(targetStorage) > 0 {
+			targetStorageString := strings.Join(targetStorage, ",")
+			migrationBody.TargetStorage = &targetStorageString
+		}
+
+		if withLocalDisks {
+			migrationBody.WithLocalDisks = &withLocalDisks
+		}
+	}
+
+	timeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutMigrate).(int)
+
+	return veClient.MigrateVMContext(ctx, sourceNodeName, vmID, migrationBody, timeout)
+}
+
+func resourceVirtualEnvironmentVMRecreateDisk(veClient *proxmox.VirtualEnvironmentClient, nodeName string, vmID int, disk string, updateBody *proxmox.VirtualEnvironmentVMUpdateRequestBody) error {
+	// The volume must be removed physically, as it would otherwise be kept as an unused disk.
+	force := proxmox.CustomBool(true)
+
+	err := veClient.UpdateVM(nodeName, vmID, &proxmox.VirtualEnvironmentVMUpdateRequestBody{
+		Delete:    []string{disk},
+		Overwrite: &force,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return veClient.UpdateVM(nodeName, vmID, updateBody)
+}
+
 func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 	veClient, err := config.GetVEClient()
 
 	if err != nil {
@@ -3183,6 +4979,22 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 		return err
 	}
 
+	// Migrate the VM to the new node before applying any other changes.
+	// The new node name must only be stored once the migration has succeeded, as the VM cannot be found otherwise.
+	if d.HasChange(mkResourceVirtualEnvironmentVMNodeName) {
+		oldNodeName, _ := d.GetChange(mkResourceVirtualEnvironmentVMNodeName)
+
+		d.Partial(true)
+
+		err = resourceVirtualEnvironmentVMMigrate(ctx, d, m, oldNodeName.(string), nodeName, vmID)
+
+		if err != nil {
+			return err
+		}
+
+		d.SetPartial(mkResourceVirtualEnvironmentVMNodeName)
+	}
+
 	updateBody := &proxmox.VirtualEnvironmentVMUpdateRequestBody{
 		IDEDevices: proxmox.CustomStorageDevices{
 			"ide0": proxmox.CustomStorageDevice{
@@ -3337,6 +5149,7 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 		cpuCores := cpuBlock[mkResourceVirtualEnvironmentVMCPUCores].(int)
 		cpuFlags := cpuBlock[mkResourceVirtualEnvironmentVMCPUFlags].([]interface{})
 		cpuHotplugged := cpuBlock[mkResourceVirtualEnvironmentVMCPUHotplugged].(int)
+		cpuNUMA := proxmox.CustomBool(cpuBlock[mkResourceVirtualEnvironmentVMCPUNUMA].(bool))
 		cpuSockets := cpuBlock[mkResourceVirtualEnvironmentVMCPUSockets].(int)
 		cpuType := cpuBlock[mkResourceVirtualEnvironmentVMCPUType].(string)
 		cpuUnits := cpuBlock[mkResourceVirtualEnvironmentVMCPUUnits].(int)
@@ -3349,6 +5162,7 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 		updateBody.CPUCores = &cpuCores
 		updateBody.CPUSockets = &cpuSockets
 		updateBody.CPUUnits = &cpuUnits
+		updateBody.NUMAEnabled = &cpuNUMA
 
 		if cpuHotplugged > 0 {
 			updateBody.VirtualCPUCount = &cpuHotplugged
@@ -3431,6 +5245,32 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 		rebootRequired = true
 	}
 
+	// Prepare the new EFI disk, if the VM does not have one, as existing disks are moved or recreated after updating the configuration.
+	if d.HasChange(mkResourceVirtualEnvironmentVMEFIDisk) && vmConfig.EFIDisk == nil {
+		updateBody.EFIDisk, err = resourceVirtualEnvironmentVMGetEFIDiskObject(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		rebootRequired = true
+	}
+
+	// Prepare the new host PCI devices.
+	if d.HasChange(mkResourceVirtualEnvironmentVMHostPCIDevice) {
+		updateBody.PCIDevices, err = resourceVirtualEnvironmentVMGetHostPCIDeviceObjects(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		for i := len(updateBody.PCIDevices); i < maxResourceVirtualEnvironmentVMHostPCIDevices; i++ {
+			delete = append(delete, fmt.Sprintf("hostpci%d", i))
+		}
+
+		rebootRequired = true
+	}
+
 	// Prepare the new cloud-init configuration.
 	if d.HasChange(mkResourceVirtualEnvironmentVMInitialization) {
 		initializationConfig, err := resourceVirtualEnvironmentVMGetCloudInitConfig(d, m)
@@ -3514,6 +5354,21 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 		rebootRequired = true
 	}
 
+	// Prepare the new NUMA nodes.
+	if d.HasChange(mkResourceVirtualEnvironmentVMNUMADevice) {
+		updateBody.NUMADevices, err = resourceVirtualEnvironmentVMGetNUMADeviceObjects(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		for i := len(updateBody.NUMADevices); i < maxResourceVirtualEnvironmentVMNUMADevices; i++ {
+			delete = append(delete, fmt.Sprintf("numa%d", i))
+		}
+
+		rebootRequired = true
+	}
+
 	// Prepare the new operating system configuration.
 	if d.HasChange(mkResourceVirtualEnvironmentVMOperatingSystem) {
 		operatingSystem, err := getSchemaBlock(resource, d, m, []string{mkResourceVirtualEnvironmentVMOperatingSystem}, 0, true)
@@ -3529,6 +5384,21 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 		rebootRequired = true
 	}
 
+	// Prepare the new RNG device.
+	if d.HasChange(mkResourceVirtualEnvironmentVMRNGDevice) {
+		updateBody.RNGDevice, err = resourceVirtualEnvironmentVMGetRNGDeviceObject(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		if updateBody.RNGDevice == nil {
+			delete = append(delete, "rng0")
+		}
+
+		rebootRequired = true
+	}
+
 	// Prepare the new serial devices.
 	if d.HasChange(mkResourceVirtualEnvironmentVMSerialDevice) {
 		updateBody.SerialDevices, err = resourceVirtualEnvironmentVMGetSerialDeviceList(d, m)
@@ -3544,6 +5414,41 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 		rebootRequired = true
 	}
 
+	// Prepare the new SMBIOS settings.
+	if d.HasChange(mkResourceVirtualEnvironmentVMSMBIOS) {
+		updateBody.SMBIOS, err = resourceVirtualEnvironmentVMGetSMBIOSObject(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		rebootRequired = true
+	}
+
+	// Prepare the new TPM state, if the VM does not have one, as existing states are moved or recreated after updating the configuration.
+	if d.HasChange(mkResourceVirtualEnvironmentVMTPMState) && vmConfig.TPMState == nil {
+		updateBody.TPMState, err = resourceVirtualEnvironmentVMGetTPMStateObject(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		rebootRequired = true
+	}
+
+	// Prepare the new USB devices, which are hot-pluggable by default and therefore do not require a reboot.
+	if d.HasChange(mkResourceVirtualEnvironmentVMUSBDevice) {
+		updateBody.USBDevices, err = resourceVirtualEnvironmentVMGetUSBDeviceObjects(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		for i := len(updateBody.USBDevices); i < maxResourceVirtualEnvironmentVMUSBDevices; i++ {
+			delete = append(delete, fmt.Sprintf("usb%d", i))
+		}
+	}
+
 	// Prepare the new VGA configuration.
 	if d.HasChange(mkResourceVirtualEnvironmentVMVGA) {
 		updateBody.VGADevice, err = resourceVirtualEnvironmentVMGetVGADeviceObject(d, m)
@@ -3555,6 +5460,21 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 		rebootRequired = true
 	}
 
+	// Prepare the new watchdog device.
+	if d.HasChange(mkResourceVirtualEnvironmentVMWatchdogDevice) {
+		updateBody.WatchdogDevice, err = resourceVirtualEnvironmentVMGetWatchdogDeviceObject(d, m)
+
+		if err != nil {
+			return err
+		}
+
+		if updateBody.WatchdogDevice == nil {
+			delete = append(delete, "watchdog")
+		}
+
+		rebootRequired = true
+	}
+
 	// Update the configuration now that everything has been prepared.
 	updateBody.Delete = delete
 
@@ -3570,7 +5490,7 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 	if d.HasChange(mkResourceVirtualEnvironmentVMStarted) && !bool(template) {
 		if started {
 			startVMTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutStartVM).(int)
-			err = veClient.StartVM(nodeName, vmID, startVMTimeout)
+			err = veClient.StartVMContext(ctx, nodeName, vmID, startVMTimeout)
 
 			if err != nil {
 				return err
@@ -3579,7 +5499,7 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 			forceStop := proxmox.CustomBool(true)
 			shutdownTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutShutdownVM).(int)
 
-			err = veClient.ShutdownVM(nodeName, vmID, &proxmox.VirtualEnvironmentVMShutdownRequestBody{
+			err = veClient.ShutdownVMContext(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentVMShutdownRequestBody{
 				ForceStop: &forceStop,
 				Timeout:   &shutdownTimeout,
 			}, (shutdownTimeout + 30))
@@ -3593,10 +5513,10 @@ func resourceVirtualEnvironmentVMUpdate(d *schema.ResourceData, m interface{}) e
 	}
 
 	// Change the disk locations and/or sizes, if necessary.
-	return resourceVirtualEnvironmentVMUpdateDiskLocationAndSize(d, m, vmConfig, !bool(template) && rebootRequired)
+	return resourceVirtualEnvironmentVMUpdateDiskLocationAndSize(ctx, d, m, vmConfig, !bool(template) && rebootRequired)
 }
 
-func resourceVirtualEnvironmentVMUpdateDiskLocationAndSize(d *schema.ResourceData, m interface{}, vmConfig *proxmox.VirtualEnvironmentVMGetResponseData, reboot bool) error {
+func resourceVirtualEnvironmentVMUpdateDiskLocationAndSize(ctx context.Context, d *schema.ResourceData, m interface{}, vmConfig *proxmox.VirtualEnvironmentVMGetResponseData, reboot bool) error {
 	config := m.(providerConfiguration)
 	veClient, err := config.GetVEClient()
 
@@ -3613,7 +5533,11 @@ func resourceVirtualEnvironmentVMUpdateDiskLocationAndSize(d *schema.ResourceDat
 		return err
 	}
 
-	// Determine if any of the disks are changing location and/or size, and initiate the necessary actions.
+	diskMoveBodies := []*proxmox.VirtualEnvironmentVMMoveDiskRequestBody{}
+	diskRecreateBodies := map[string]*proxmox.VirtualEnvironmentVMUpdateRequestBody{}
+	diskResizeBodies := []*proxmox.VirtualEnvironmentVMResizeDiskRequestBody{}
+
+	// Determine if any of the disks are changing location and/or size.
 	if d.HasChange(mkResourceVirtualEnvironmentVMDisk) {
 		diskOld, diskNew := d.GetChange(mkResourceVirtualEnvironmentVMDisk)
 
@@ -3627,9 +5551,6 @@ func resourceVirtualEnvironmentVMUpdateDiskLocationAndSize(d *schema.ResourceDat
 			return err
 		}
 
-		diskMoveBodies := []*proxmox.VirtualEnvironmentVMMoveDiskRequestBody{}
-		diskResizeBodies := []*proxmox.VirtualEnvironmentVMResizeDiskRequestBody{}
-
 		for prefix, diskMap := range diskOldEntries {
 			for oldKey, oldDisk := range diskMap {
 				if _, present := diskNewEntries[prefix][oldKey]; !present {
@@ -3654,57 +5575,149 @@ func resourceVirtualEnvironmentVMUpdateDiskLocationAndSize(d *schema.ResourceDat
 				}
 			}
 		}
+	}
+
+	// Determine if the EFI disk or the TPM state are changing location or must be recreated, as their type and version cannot be changed.
+	if d.HasChange(mkResourceVirtualEnvironmentVMEFIDisk) {
+		efiDiskOld, efiDiskNew := d.GetChange(mkResourceVirtualEnvironmentVMEFIDisk)
+		efiDiskOldList := efiDiskOld.([]interface{})
+		efiDiskNewList := efiDiskNew.([]interface{})
+
+		if len(efiDiskOldList) > 0 && efiDiskOldList[0] != nil && len(efiDiskNewList) > 0 && efiDiskNewList[0] != nil {
+			efiDiskOldBlock := efiDiskOldList[0].(map[string]interface{})
+			efiDiskNewBlock := efiDiskNewList[0].(map[string]interface{})
 
-		if len(diskMoveBodies) > 0 || len(diskResizeBodies) > 0 {
-			if !template {
-				forceStop := proxmox.CustomBool(true)
-				shutdownTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutShutdownVM).(int)
+			efiDiskOldDatastoreID := efiDiskOldBlock[mkResourceVirtualEnvironmentVMEFIDiskDatastoreID].(string)
+			efiDiskOldFileFormat := efiDiskOldBlock[mkResourceVirtualEnvironmentVMEFIDiskFileFormat].(string)
+			efiDiskOldPreEnrolledKeys := efiDiskOldBlock[mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys].(bool)
+			efiDiskOldType := efiDiskOldBlock[mkResourceVirtualEnvironmentVMEFIDiskType].(string)
+			efiDiskNewDatastoreID := efiDiskNewBlock[mkResourceVirtualEnvironmentVMEFIDiskDatastoreID].(string)
+			efiDiskNewFileFormat := efiDiskNewBlock[mkResourceVirtualEnvironmentVMEFIDiskFileFormat].(string)
+			efiDiskNewPreEnrolledKeys := efiDiskNewBlock[mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys].(bool)
+			efiDiskNewType := efiDiskNewBlock[mkResourceVirtualEnvironmentVMEFIDiskType].(string)
 
-				err = veClient.ShutdownVM(nodeName, vmID, &proxmox.VirtualEnvironmentVMShutdownRequestBody{
-					ForceStop: &forceStop,
-					Timeout:   &shutdownTimeout,
-				}, (shutdownTimeout + 30))
+			if efiDiskOldType != efiDiskNewType || efiDiskOldPreEnrolledKeys != efiDiskNewPreEnrolledKeys {
+				efiDiskObject, err := resourceVirtualEnvironmentVMGetEFIDiskObject(d, m)
 
 				if err != nil {
 					return err
 				}
-			}
 
-			reboot = false
-		}
+				diskRecreateBodies["efidisk0"] = &proxmox.VirtualEnvironmentVMUpdateRequestBody{
+					EFIDisk: efiDiskObject,
+				}
+			} else if efiDiskOldDatastoreID != efiDiskNewDatastoreID || (efiDiskNewFileFormat != "" && efiDiskOldFileFormat != efiDiskNewFileFormat) {
+				deleteOriginalDisk := proxmox.CustomBool(true)
 
-		for _, reqBody := range diskMoveBodies {
-			moveDiskTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutMoveDisk).(int)
-			err = veClient.MoveVMDisk(nodeName, vmID, reqBody, moveDiskTimeout)
+				diskMoveBody := &proxmox.VirtualEnvironmentVMMoveDiskRequestBody{
+					DeleteOriginalDisk: &deleteOriginalDisk,
+					Disk:               "efidisk0",
+					TargetStorage:      efiDiskNewDatastoreID,
+				}
 
-			if err != nil {
-				return err
+				if efiDiskNewFileFormat != "" && efiDiskOldFileFormat != efiDiskNewFileFormat {
+					diskMoveBody.TargetStorageFormat = &efiDiskNewFileFormat
+				}
+
+				diskMoveBodies = append(diskMoveBodies, diskMoveBody)
 			}
 		}
+	}
 
-		for _, reqBody := range diskResizeBodies {
-			err = veClient.ResizeVMDisk(nodeName, vmID, reqBody)
+	if d.HasChange(mkResourceVirtualEnvironmentVMTPMState) {
+		tpmStateOld, tpmStateNew := d.GetChange(mkResourceVirtualEnvironmentVMTPMState)
+		tpmStateOldList := tpmStateOld.([]interface{})
+		tpmStateNewList := tpmStateNew.([]interface{})
 
-			if err != nil {
-				return err
+		if len(tpmStateOldList) > 0 && tpmStateOldList[0] != nil && len(tpmStateNewList) > 0 && tpmStateNewList[0] != nil {
+			tpmStateOldBlock := tpmStateOldList[0].(map[string]interface{})
+			tpmStateNewBlock := tpmStateNewList[0].(map[string]interface{})
+
+			tpmStateOldDatastoreID := tpmStateOldBlock[mkResourceVirtualEnvironmentVMTPMStateDatastoreID].(string)
+			tpmStateOldVersion := tpmStateOldBlock[mkResourceVirtualEnvironmentVMTPMStateVersion].(string)
+			tpmStateNewDatastoreID := tpmStateNewBlock[mkResourceVirtualEnvironmentVMTPMStateDatastoreID].(string)
+			tpmStateNewVersion := tpmStateNewBlock[mkResourceVirtualEnvironmentVMTPMStateVersion].(string)
+
+			if tpmStateOldVersion != tpmStateNewVersion {
+				tpmStateObject, err := resourceVirtualEnvironmentVMGetTPMStateObject(d, m)
+
+				if err != nil {
+					return err
+				}
+
+				diskRecreateBodies["tpmstate0"] = &proxmox.VirtualEnvironmentVMUpdateRequestBody{
+					TPMState: tpmStateObject,
+				}
+			} else if tpmStateOldDatastoreID != tpmStateNewDatastoreID {
+				deleteOriginalDisk := proxmox.CustomBool(true)
+
+				diskMoveBodies = append(diskMoveBodies, &proxmox.VirtualEnvironmentVMMoveDiskRequestBody{
+					DeleteOriginalDisk: &deleteOriginalDisk,
+					Disk:               "tpmstate0",
+					TargetStorage:      tpmStateNewDatastoreID,
+				})
 			}
 		}
+	}
 
-		if (len(diskMoveBodies) > 0 || len(diskResizeBodies) > 0) && started && !template {
-			startVMTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutStartVM).(int)
-			err = veClient.StartVM(nodeName, vmID, startVMTimeout)
+	// Shut down the virtual machine, if necessary, and initiate the disk actions.
+	if len(diskMoveBodies) > 0 || len(diskRecreateBodies) > 0 || len(diskResizeBodies) > 0 {
+		if !template {
+			forceStop := proxmox.CustomBool(true)
+			shutdownTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutShutdownVM).(int)
+
+			err = veClient.ShutdownVMContext(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentVMShutdownRequestBody{
+				ForceStop: &forceStop,
+				Timeout:   &shutdownTimeout,
+			}, (shutdownTimeout + 30))
 
 			if err != nil {
 				return err
 			}
 		}
+
+		reboot = false
+	}
+
+	for disk, reqBody := range diskRecreateBodies {
+		err = resourceVirtualEnvironmentVMRecreateDisk(veClient, nodeName, vmID, disk, reqBody)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, reqBody := range diskMoveBodies {
+		moveDiskTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutMoveDisk).(int)
+		err = veClient.MoveVMDiskContext(ctx, nodeName, vmID, reqBody, moveDiskTimeout)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, reqBody := range diskResizeBodies {
+		err = veClient.ResizeVMDisk(nodeName, vmID, reqBody)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if (len(diskMoveBodies) > 0 || len(diskRecreateBodies) > 0 || len(diskResizeBodies) > 0) && started && !template {
+		startVMTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutStartVM).(int)
+		err = veClient.StartVMContext(ctx, nodeName, vmID, startVMTimeout)
+
+		if err != nil {
+			return err
+		}
 	}
 
 	// Perform a regular reboot in case it's necessary and haven't already been done.
 	if reboot {
 		rebootTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutReboot).(int)
 
-		err := veClient.RebootVM(nodeName, vmID, &proxmox.VirtualEnvironmentVMRebootRequestBody{
+		err := veClient.RebootVMContext(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentVMRebootRequestBody{
 			Timeout: &rebootTimeout,
 		}, (rebootTimeout + 30))
 
@@ -3713,11 +5726,15 @@ func resourceVirtualEnvironmentVMUpdateDiskLocationAndSize(d *schema.ResourceDat
 		}
 	}
 
+	d.Partial(false)
+
 	return resourceVirtualEnvironmentVMRead(d, m)
 }
 
 func resourceVirtualEnvironmentVMDelete(d *schema.ResourceData, m interface{}) error {
 	config := m.(providerConfiguration)
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 	veClient, err := config.GetVEClient()
 
 	if err != nil {
@@ -3742,7 +5759,7 @@ func resourceVirtualEnvironmentVMDelete(d *schema.ResourceData, m interface{}) e
 		forceStop := proxmox.CustomBool(true)
 		shutdownTimeout := d.Get(mkResourceVirtualEnvironmentVMTimeoutShutdownVM).(int)
 
-		err = veClient.ShutdownVM(nodeName, vmID, &proxmox.VirtualEnvironmentVMShutdownRequestBody{
+		err = veClient.ShutdownVMContext(ctx, nodeName, vmID, &proxmox.VirtualEnvironmentVMShutdownRequestBody{
 			ForceStop: &forceStop,
 			Timeout:   &shutdownTimeout,
 		}, (shutdownTimeout + 30))
@@ -3752,11 +5769,10 @@ func resourceVirtualEnvironmentVMDelete(d *schema.ResourceData, m interface{}) e
 		}
 	}
 
-	err = veClient.DeleteVM(nodeName, vmID)
+	task, err := veClient.DeleteVMAsync(ctx, nodeName, vmID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "HTTP 404") ||
-			(strings.Contains(err.Error(), "HTTP 500") && strings.Contains(err.Error(), "does not exist")) {
+		if proxmox.IsNotFound(err) {
 			d.SetId("")
 
 			return nil
@@ -3765,11 +5781,11 @@ func resourceVirtualEnvironmentVMDelete(d *schema.ResourceData, m interface{}) e
 		return err
 	}
 
-	// Wait for the state to become unavailable as that clearly indicates the destruction of the VM.
-	err = veClient.WaitForVMState(nodeName, vmID, "", 60, 2)
+	// Wait for the destroy task to complete as that clearly indicates the destruction of the VM.
+	err = task.WaitContext(ctx, 60, 2)
 
-	if err == nil {
-		return fmt.Errorf("Failed to delete VM \"%d\"", vmID)
+	if err != nil {
+		return err
 	}
 
 	d.SetId("")
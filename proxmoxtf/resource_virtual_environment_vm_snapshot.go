@@ -0,0 +1,398 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmoxtf
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danitso/terraform-provider-proxmox/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	dvResourceVirtualEnvironmentVMSnapshotDescription       = ""
+	dvResourceVirtualEnvironmentVMSnapshotIncludeRAM        = false
+	dvResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy = false
+	dvResourceVirtualEnvironmentVMSnapshotTimeoutCreate     = 1800
+	dvResourceVirtualEnvironmentVMSnapshotTimeoutDelete     = 1800
+	dvResourceVirtualEnvironmentVMSnapshotTimeoutRollback   = 1800
+
+	mkResourceVirtualEnvironmentVMSnapshotCreationDate      = "creation_date"
+	mkResourceVirtualEnvironmentVMSnapshotDescription       = "description"
+	mkResourceVirtualEnvironmentVMSnapshotIncludeRAM        = "include_ram"
+	mkResourceVirtualEnvironmentVMSnapshotName              = "name"
+	mkResourceVirtualEnvironmentVMSnapshotNodeName          = "node_name"
+	mkResourceVirtualEnvironmentVMSnapshotParent            = "parent"
+	mkResourceVirtualEnvironmentVMSnapshotRollback          = "rollback"
+	mkResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy = "rollback_on_destroy"
+	mkResourceVirtualEnvironmentVMSnapshotTimeoutCreate     = "timeout_create"
+	mkResourceVirtualEnvironmentVMSnapshotTimeoutDelete     = "timeout_delete"
+	mkResourceVirtualEnvironmentVMSnapshotTimeoutRollback   = "timeout_rollback"
+	mkResourceVirtualEnvironmentVMSnapshotVMID              = "vm_id"
+)
+
+func resourceVirtualEnvironmentVMSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			mkResourceVirtualEnvironmentVMSnapshotCreationDate: {
+				Type:        schema.TypeString,
+				Description: "The creation date",
+				Computed:    true,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotDescription: {
+				Type:        schema.TypeString,
+				Description: "The description",
+				Optional:    true,
+				Default:     dvResourceVirtualEnvironmentVMSnapshotDescription,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotIncludeRAM: {
+				Type:        schema.TypeBool,
+				Description: "Whether to include the RAM of the VM",
+				Optional:    true,
+				ForceNew:    true,
+				Default:     dvResourceVirtualEnvironmentVMSnapshotIncludeRAM,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotName: {
+				Type:         schema.TypeString,
+				Description:  "The snapshot name",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: resourceVirtualEnvironmentVMSnapshotGetNameValidator(),
+			},
+			mkResourceVirtualEnvironmentVMSnapshotNodeName: {
+				Type:        schema.TypeString,
+				Description: "The node name",
+				Required:    true,
+				ForceNew:    true,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotParent: {
+				Type:        schema.TypeString,
+				Description: "The name of the parent snapshot",
+				Computed:    true,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotRollback: {
+				Type:        schema.TypeMap,
+				Description: "Arbitrary values, which trigger a rollback to the snapshot when changed",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			mkResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy: {
+				Type:        schema.TypeBool,
+				Description: "Whether to roll back to the snapshot before destroying it",
+				Optional:    true,
+				Default:     dvResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotTimeoutCreate: {
+				Type:        schema.TypeInt,
+				Description: "Create snapshot timeout",
+				Optional:    true,
+				Default:     dvResourceVirtualEnvironmentVMSnapshotTimeoutCreate,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotTimeoutDelete: {
+				Type:        schema.TypeInt,
+				Description: "Delete snapshot timeout",
+				Optional:    true,
+				Default:     dvResourceVirtualEnvironmentVMSnapshotTimeoutDelete,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotTimeoutRollback: {
+				Type:        schema.TypeInt,
+				Description: "Rollback snapshot timeout",
+				Optional:    true,
+				Default:     dvResourceVirtualEnvironmentVMSnapshotTimeoutRollback,
+			},
+			mkResourceVirtualEnvironmentVMSnapshotVMID: {
+				Type:         schema.TypeInt,
+				Description:  "The VM identifier",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: getVMIDValidator(),
+			},
+		},
+		Create: resourceVirtualEnvironmentVMSnapshotCreate,
+		Read:   resourceVirtualEnvironmentVMSnapshotRead,
+		Update: resourceVirtualEnvironmentVMSnapshotUpdate,
+		Delete: resourceVirtualEnvironmentVMSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVirtualEnvironmentVMSnapshotImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
+	}
+}
+
+func resourceVirtualEnvironmentVMSnapshotCreate(d *schema.ResourceData, m interface{}) error {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
+
+	description := d.Get(mkResourceVirtualEnvironmentVMSnapshotDescription).(string)
+	includeRAM := proxmox.CustomBool(d.Get(mkResourceVirtualEnvironmentVMSnapshotIncludeRAM).(bool))
+	name := d.Get(mkResourceVirtualEnvironmentVMSnapshotName).(string)
+	nodeName := d.Get(mkResourceVirtualEnvironmentVMSnapshotNodeName).(string)
+	timeout := d.Get(mkResourceVirtualEnvironmentVMSnapshotTimeoutCreate).(int)
+	vmID := d.Get(mkResourceVirtualEnvironmentVMSnapshotVMID).(int)
+
+	body := &proxmox.VirtualEnvironmentVMSnapshotCreateRequestBody{
+		Name:    name,
+		VMState: &includeRAM,
+	}
+
+	if description != "" {
+		body.Description = &description
+	}
+
+	// The RAM of a stopped VM cannot be saved, which would cause the snapshot to be replaced on every plan.
+	if includeRAM {
+		vmStatus, err := veClient.GetVMStatusContext(ctx, nodeName, vmID)
+
+		if err != nil {
+			return err
+		}
+
+		if vmStatus.Status != "running" {
+			return fmt.Errorf("Cannot include the RAM in a snapshot of VM \"%d\", as it is not running (status: %s)", vmID, vmStatus.Status)
+		}
+	}
+
+	task, err := veClient.CreateVMSnapshotAsync(ctx, nodeName, vmID, body)
+
+	if err != nil {
+		return err
+	}
+
+	err = task.WaitContext(ctx, timeout, 5)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%d/%s", nodeName, vmID, name))
+
+	return resourceVirtualEnvironmentVMSnapshotRead(d, m)
+}
+
+func resourceVirtualEnvironmentVMSnapshotGetNameValidator() schema.SchemaValidateFunc {
+	return validation.All(
+		validation.StringLenBetween(2, 40),
+		validation.StringMatch(regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_\-]+$`), "must start with a letter and only contain letters, numbers, hyphens and underscores"),
+		validation.StringNotInSlice([]string{"current"}, true),
+	)
+}
+
+func resourceVirtualEnvironmentVMSnapshotImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	idParts := strings.Split(d.Id(), "/")
+
+	if len(idParts) != 3 || idParts[0] == "" || idParts[2] == "" {
+		return nil, fmt.Errorf("Invalid import identifier \"%s\" (valid: node_name/vm_id/name)", d.Id())
+	}
+
+	vmID, err := strconv.Atoi(idParts[1])
+
+	if err != nil || vmID < 1 {
+		return nil, fmt.Errorf("Invalid import identifier \"%s\" (valid: node_name/vm_id/name)", d.Id())
+	}
+
+	d.Set(mkResourceVirtualEnvironmentVMSnapshotName, idParts[2])
+	d.Set(mkResourceVirtualEnvironmentVMSnapshotNodeName, idParts[0])
+	d.Set(mkResourceVirtualEnvironmentVMSnapshotVMID, vmID)
+
+	// The following arguments are not available through the API, which is why they are set to their default values.
+	d.Set(mkResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy, dvResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy)
+	d.Set(mkResourceVirtualEnvironmentVMSnapshotTimeoutCreate, dvResourceVirtualEnvironmentVMSnapshotTimeoutCreate)
+	d.Set(mkResourceVirtualEnvironmentVMSnapshotTimeoutDelete, dvResourceVirtualEnvironmentVMSnapshotTimeoutDelete)
+	d.Set(mkResourceVirtualEnvironmentVMSnapshotTimeoutRollback, dvResourceVirtualEnvironmentVMSnapshotTimeoutRollback)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceVirtualEnvironmentVMSnapshotRead(d *schema.ResourceData, m interface{}) error {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return err
+	}
+
+	name := d.Get(mkResourceVirtualEnvironmentVMSnapshotName).(string)
+	nodeName := d.Get(mkResourceVirtualEnvironmentVMSnapshotNodeName).(string)
+	vmID := d.Get(mkResourceVirtualEnvironmentVMSnapshotVMID).(int)
+
+	list, err := veClient.ListVMSnapshots(nodeName, vmID)
+
+	if err != nil {
+		if proxmox.IsNotFound(err) {
+			d.SetId("")
+
+			return nil
+		}
+
+		return err
+	}
+
+	for _, v := range list {
+		if v.Name != name {
+			continue
+		}
+
+		if v.Time != nil {
+			d.Set(mkResourceVirtualEnvironmentVMSnapshotCreationDate, time.Time(*v.Time).UTC().Format(time.RFC3339))
+		} else {
+			d.Set(mkResourceVirtualEnvironmentVMSnapshotCreationDate, "")
+		}
+
+		if v.Description != nil {
+			d.Set(mkResourceVirtualEnvironmentVMSnapshotDescription, strings.TrimSpace(*v.Description))
+		} else {
+			d.Set(mkResourceVirtualEnvironmentVMSnapshotDescription, "")
+		}
+
+		if v.VMState != nil {
+			d.Set(mkResourceVirtualEnvironmentVMSnapshotIncludeRAM, bool(*v.VMState))
+		} else {
+			d.Set(mkResourceVirtualEnvironmentVMSnapshotIncludeRAM, false)
+		}
+
+		if v.Parent != nil {
+			d.Set(mkResourceVirtualEnvironmentVMSnapshotParent, *v.Parent)
+		} else {
+			d.Set(mkResourceVirtualEnvironmentVMSnapshotParent, "")
+		}
+
+		return nil
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceVirtualEnvironmentVMSnapshotRollback(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return err
+	}
+
+	name := d.Get(mkResourceVirtualEnvironmentVMSnapshotName).(string)
+	nodeName := d.Get(mkResourceVirtualEnvironmentVMSnapshotNodeName).(string)
+	timeout := d.Get(mkResourceVirtualEnvironmentVMSnapshotTimeoutRollback).(int)
+	vmID := d.Get(mkResourceVirtualEnvironmentVMSnapshotVMID).(int)
+
+	task, err := veClient.RollbackVMSnapshotAsync(ctx, nodeName, vmID, name)
+
+	if err != nil {
+		return err
+	}
+
+	return task.WaitContext(ctx, timeout, 5)
+}
+
+func resourceVirtualEnvironmentVMSnapshotUpdate(d *schema.ResourceData, m interface{}) error {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
+
+	name := d.Get(mkResourceVirtualEnvironmentVMSnapshotName).(string)
+	nodeName := d.Get(mkResourceVirtualEnvironmentVMSnapshotNodeName).(string)
+	vmID := d.Get(mkResourceVirtualEnvironmentVMSnapshotVMID).(int)
+
+	if d.HasChange(mkResourceVirtualEnvironmentVMSnapshotDescription) {
+		description := d.Get(mkResourceVirtualEnvironmentVMSnapshotDescription).(string)
+
+		err = veClient.UpdateVMSnapshot(nodeName, vmID, name, &proxmox.VirtualEnvironmentVMSnapshotUpdateRequestBody{
+			Description: &description,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange(mkResourceVirtualEnvironmentVMSnapshotRollback) {
+		err = resourceVirtualEnvironmentVMSnapshotRollback(ctx, d, m)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceVirtualEnvironmentVMSnapshotRead(d, m)
+}
+
+func resourceVirtualEnvironmentVMSnapshotDelete(d *schema.ResourceData, m interface{}) error {
+	config := m.(providerConfiguration)
+	veClient, err := config.GetVEClient()
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := config.GetTimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
+
+	name := d.Get(mkResourceVirtualEnvironmentVMSnapshotName).(string)
+	nodeName := d.Get(mkResourceVirtualEnvironmentVMSnapshotNodeName).(string)
+	rollbackOnDestroy := d.Get(mkResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy).(bool)
+	timeout := d.Get(mkResourceVirtualEnvironmentVMSnapshotTimeoutDelete).(int)
+	vmID := d.Get(mkResourceVirtualEnvironmentVMSnapshotVMID).(int)
+
+	if rollbackOnDestroy {
+		err = resourceVirtualEnvironmentVMSnapshotRollback(ctx, d, m)
+
+		if err != nil {
+			if proxmox.IsNotFound(err) {
+				d.SetId("")
+
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	task, err := veClient.DeleteVMSnapshotAsync(ctx, nodeName, vmID, name)
+
+	if err != nil {
+		if proxmox.IsNotFound(err) {
+			d.SetId("")
+
+			return nil
+		}
+
+		return err
+	}
+
+	err = task.WaitContext(ctx, timeout, 5)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
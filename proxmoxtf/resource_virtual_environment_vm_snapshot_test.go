@@ -0,0 +1,61 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package proxmoxtf
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// TestResourceVirtualEnvironmentVMSnapshotInstantiation tests whether the ResourceVirtualEnvironmentVMSnapshot instance can be instantiated.
+func TestResourceVirtualEnvironmentVMSnapshotInstantiation(t *testing.T) {
+	s := resourceVirtualEnvironmentVMSnapshot()
+
+	if s == nil {
+		t.Fatalf("Cannot instantiate resourceVirtualEnvironmentVMSnapshot")
+	}
+}
+
+// TestResourceVirtualEnvironmentVMSnapshotSchema tests the resourceVirtualEnvironmentVMSnapshot schema.
+func TestResourceVirtualEnvironmentVMSnapshotSchema(t *testing.T) {
+	s := resourceVirtualEnvironmentVMSnapshot()
+
+	testRequiredArguments(t, s, []string{
+		mkResourceVirtualEnvironmentVMSnapshotName,
+		mkResourceVirtualEnvironmentVMSnapshotNodeName,
+		mkResourceVirtualEnvironmentVMSnapshotVMID,
+	})
+
+	testOptionalArguments(t, s, []string{
+		mkResourceVirtualEnvironmentVMSnapshotDescription,
+		mkResourceVirtualEnvironmentVMSnapshotIncludeRAM,
+		mkResourceVirtualEnvironmentVMSnapshotRollback,
+		mkResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy,
+		mkResourceVirtualEnvironmentVMSnapshotTimeoutCreate,
+		mkResourceVirtualEnvironmentVMSnapshotTimeoutDelete,
+		mkResourceVirtualEnvironmentVMSnapshotTimeoutRollback,
+	})
+
+	testComputedAttributes(t, s, []string{
+		mkResourceVirtualEnvironmentVMSnapshotCreationDate,
+		mkResourceVirtualEnvironmentVMSnapshotParent,
+	})
+
+	testValueTypes(t, s, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMSnapshotCreationDate:      schema.TypeString,
+		mkResourceVirtualEnvironmentVMSnapshotDescription:       schema.TypeString,
+		mkResourceVirtualEnvironmentVMSnapshotIncludeRAM:        schema.TypeBool,
+		mkResourceVirtualEnvironmentVMSnapshotName:              schema.TypeString,
+		mkResourceVirtualEnvironmentVMSnapshotNodeName:          schema.TypeString,
+		mkResourceVirtualEnvironmentVMSnapshotParent:            schema.TypeString,
+		mkResourceVirtualEnvironmentVMSnapshotRollback:          schema.TypeMap,
+		mkResourceVirtualEnvironmentVMSnapshotRollbackOnDestroy: schema.TypeBool,
+		mkResourceVirtualEnvironmentVMSnapshotTimeoutCreate:     schema.TypeInt,
+		mkResourceVirtualEnvironmentVMSnapshotTimeoutDelete:     schema.TypeInt,
+		mkResourceVirtualEnvironmentVMSnapshotTimeoutRollback:   schema.TypeInt,
+		mkResourceVirtualEnvironmentVMSnapshotVMID:              schema.TypeInt,
+	})
+}
@@ -5,9 +5,15 @@
 package proxmoxtf
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/danitso/terraform-provider-proxmox/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 // TestResourceVirtualEnvironmentVMInstantiation tests whether the ResourceVirtualEnvironmentVM instance can be instantiated.
@@ -37,18 +43,27 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMCPU,
 		mkResourceVirtualEnvironmentVMDescription,
 		mkResourceVirtualEnvironmentVMDisk,
+		mkResourceVirtualEnvironmentVMEFIDisk,
+		mkResourceVirtualEnvironmentVMHostPCIDevice,
 		mkResourceVirtualEnvironmentVMInitialization,
 		mkResourceVirtualEnvironmentVMKeyboardLayout,
 		mkResourceVirtualEnvironmentVMMemory,
+		mkResourceVirtualEnvironmentVMMigration,
 		mkResourceVirtualEnvironmentVMName,
 		mkResourceVirtualEnvironmentVMNetworkDevice,
+		mkResourceVirtualEnvironmentVMNUMADevice,
 		mkResourceVirtualEnvironmentVMOperatingSystem,
 		mkResourceVirtualEnvironmentVMPoolID,
+		mkResourceVirtualEnvironmentVMRNGDevice,
 		mkResourceVirtualEnvironmentVMSerialDevice,
+		mkResourceVirtualEnvironmentVMSMBIOS,
 		mkResourceVirtualEnvironmentVMStarted,
 		mkResourceVirtualEnvironmentVMTabletDevice,
 		mkResourceVirtualEnvironmentVMTemplate,
+		mkResourceVirtualEnvironmentVMTPMState,
+		mkResourceVirtualEnvironmentVMUSBDevice,
 		mkResourceVirtualEnvironmentVMVMID,
+		mkResourceVirtualEnvironmentVMWatchdogDevice,
 	})
 
 	testComputedAttributes(t, s, []string{
@@ -67,22 +82,31 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMCPU:                   schema.TypeList,
 		mkResourceVirtualEnvironmentVMDescription:           schema.TypeString,
 		mkResourceVirtualEnvironmentVMDisk:                  schema.TypeList,
+		mkResourceVirtualEnvironmentVMEFIDisk:               schema.TypeList,
+		mkResourceVirtualEnvironmentVMHostPCIDevice:         schema.TypeList,
 		mkResourceVirtualEnvironmentVMInitialization:        schema.TypeList,
 		mkResourceVirtualEnvironmentVMIPv4Addresses:         schema.TypeList,
 		mkResourceVirtualEnvironmentVMIPv6Addresses:         schema.TypeList,
 		mkResourceVirtualEnvironmentVMKeyboardLayout:        schema.TypeString,
 		mkResourceVirtualEnvironmentVMMemory:                schema.TypeList,
+		mkResourceVirtualEnvironmentVMMigration:             schema.TypeList,
 		mkResourceVirtualEnvironmentVMName:                  schema.TypeString,
 		mkResourceVirtualEnvironmentVMNetworkDevice:         schema.TypeList,
 		mkResourceVirtualEnvironmentVMMACAddresses:          schema.TypeList,
 		mkResourceVirtualEnvironmentVMNetworkInterfaceNames: schema.TypeList,
+		mkResourceVirtualEnvironmentVMNUMADevice:            schema.TypeList,
 		mkResourceVirtualEnvironmentVMOperatingSystem:       schema.TypeList,
 		mkResourceVirtualEnvironmentVMPoolID:                schema.TypeString,
+		mkResourceVirtualEnvironmentVMRNGDevice:             schema.TypeList,
 		mkResourceVirtualEnvironmentVMSerialDevice:          schema.TypeList,
+		mkResourceVirtualEnvironmentVMSMBIOS:                schema.TypeList,
 		mkResourceVirtualEnvironmentVMStarted:               schema.TypeBool,
 		mkResourceVirtualEnvironmentVMTabletDevice:          schema.TypeBool,
 		mkResourceVirtualEnvironmentVMTemplate:              schema.TypeBool,
+		mkResourceVirtualEnvironmentVMTPMState:              schema.TypeList,
+		mkResourceVirtualEnvironmentVMUSBDevice:             schema.TypeList,
 		mkResourceVirtualEnvironmentVMVMID:                  schema.TypeInt,
+		mkResourceVirtualEnvironmentVMWatchdogDevice:        schema.TypeList,
 	})
 
 	agentSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMAgent)
@@ -148,6 +172,7 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMCPUCores,
 		mkResourceVirtualEnvironmentVMCPUFlags,
 		mkResourceVirtualEnvironmentVMCPUHotplugged,
+		mkResourceVirtualEnvironmentVMCPUNUMA,
 		mkResourceVirtualEnvironmentVMCPUSockets,
 		mkResourceVirtualEnvironmentVMCPUType,
 		mkResourceVirtualEnvironmentVMCPUUnits,
@@ -158,6 +183,7 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMCPUCores:        schema.TypeInt,
 		mkResourceVirtualEnvironmentVMCPUFlags:        schema.TypeList,
 		mkResourceVirtualEnvironmentVMCPUHotplugged:   schema.TypeInt,
+		mkResourceVirtualEnvironmentVMCPUNUMA:         schema.TypeBool,
 		mkResourceVirtualEnvironmentVMCPUSockets:      schema.TypeInt,
 		mkResourceVirtualEnvironmentVMCPUType:         schema.TypeString,
 		mkResourceVirtualEnvironmentVMCPUUnits:        schema.TypeInt,
@@ -195,6 +221,45 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMDiskSpeedWriteBurstable: schema.TypeInt,
 	})
 
+	efiDiskSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMEFIDisk)
+
+	testOptionalArguments(t, efiDiskSchema, []string{
+		mkResourceVirtualEnvironmentVMEFIDiskDatastoreID,
+		mkResourceVirtualEnvironmentVMEFIDiskFileFormat,
+		mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys,
+		mkResourceVirtualEnvironmentVMEFIDiskType,
+	})
+
+	testValueTypes(t, efiDiskSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMEFIDiskDatastoreID:     schema.TypeString,
+		mkResourceVirtualEnvironmentVMEFIDiskFileFormat:      schema.TypeString,
+		mkResourceVirtualEnvironmentVMEFIDiskPreEnrolledKeys: schema.TypeBool,
+		mkResourceVirtualEnvironmentVMEFIDiskType:            schema.TypeString,
+	})
+
+	hostPCIDeviceSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMHostPCIDevice)
+
+	testRequiredArguments(t, hostPCIDeviceSchema, []string{
+		mkResourceVirtualEnvironmentVMHostPCIDeviceID,
+	})
+
+	testOptionalArguments(t, hostPCIDeviceSchema, []string{
+		mkResourceVirtualEnvironmentVMHostPCIDeviceMDev,
+		mkResourceVirtualEnvironmentVMHostPCIDevicePCIE,
+		mkResourceVirtualEnvironmentVMHostPCIDeviceROMBAR,
+		mkResourceVirtualEnvironmentVMHostPCIDeviceROMFile,
+		mkResourceVirtualEnvironmentVMHostPCIDeviceXVGA,
+	})
+
+	testValueTypes(t, hostPCIDeviceSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMHostPCIDeviceID:      schema.TypeString,
+		mkResourceVirtualEnvironmentVMHostPCIDeviceMDev:    schema.TypeString,
+		mkResourceVirtualEnvironmentVMHostPCIDevicePCIE:    schema.TypeBool,
+		mkResourceVirtualEnvironmentVMHostPCIDeviceROMBAR:  schema.TypeBool,
+		mkResourceVirtualEnvironmentVMHostPCIDeviceROMFile: schema.TypeString,
+		mkResourceVirtualEnvironmentVMHostPCIDeviceXVGA:    schema.TypeBool,
+	})
+
 	initializationSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMInitialization)
 
 	testOptionalArguments(t, initializationSchema, []string{
@@ -287,6 +352,20 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMMemoryShared:    schema.TypeInt,
 	})
 
+	migrationSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMMigration)
+
+	testOptionalArguments(t, migrationSchema, []string{
+		mkResourceVirtualEnvironmentVMMigrationDatastoreMapping,
+		mkResourceVirtualEnvironmentVMMigrationTargetDatastoreID,
+		mkResourceVirtualEnvironmentVMMigrationWithLocalDisks,
+	})
+
+	testValueTypes(t, migrationSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMMigrationDatastoreMapping:  schema.TypeMap,
+		mkResourceVirtualEnvironmentVMMigrationTargetDatastoreID: schema.TypeString,
+		mkResourceVirtualEnvironmentVMMigrationWithLocalDisks:    schema.TypeBool,
+	})
+
 	networkDeviceSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMNetworkDevice)
 
 	testOptionalArguments(t, networkDeviceSchema, []string{
@@ -307,6 +386,25 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMNetworkDeviceVLANID:     schema.TypeInt,
 	})
 
+	numaDeviceSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMNUMADevice)
+
+	testRequiredArguments(t, numaDeviceSchema, []string{
+		mkResourceVirtualEnvironmentVMNUMADeviceCPUs,
+		mkResourceVirtualEnvironmentVMNUMADeviceMemory,
+	})
+
+	testOptionalArguments(t, numaDeviceSchema, []string{
+		mkResourceVirtualEnvironmentVMNUMADeviceHostNodes,
+		mkResourceVirtualEnvironmentVMNUMADevicePolicy,
+	})
+
+	testValueTypes(t, numaDeviceSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMNUMADeviceCPUs:      schema.TypeString,
+		mkResourceVirtualEnvironmentVMNUMADeviceHostNodes: schema.TypeString,
+		mkResourceVirtualEnvironmentVMNUMADeviceMemory:    schema.TypeInt,
+		mkResourceVirtualEnvironmentVMNUMADevicePolicy:    schema.TypeString,
+	})
+
 	operatingSystemSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMOperatingSystem)
 
 	testOptionalArguments(t, operatingSystemSchema, []string{
@@ -317,6 +415,20 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMOperatingSystemType: schema.TypeString,
 	})
 
+	rngDeviceSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMRNGDevice)
+
+	testOptionalArguments(t, rngDeviceSchema, []string{
+		mkResourceVirtualEnvironmentVMRNGDeviceMaxBytes,
+		mkResourceVirtualEnvironmentVMRNGDevicePeriod,
+		mkResourceVirtualEnvironmentVMRNGDeviceSource,
+	})
+
+	testValueTypes(t, rngDeviceSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMRNGDeviceMaxBytes: schema.TypeInt,
+		mkResourceVirtualEnvironmentVMRNGDevicePeriod:   schema.TypeInt,
+		mkResourceVirtualEnvironmentVMRNGDeviceSource:   schema.TypeString,
+	})
+
 	serialDeviceSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMSerialDevice)
 
 	testOptionalArguments(t, serialDeviceSchema, []string{
@@ -327,6 +439,55 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMSerialDeviceDevice: schema.TypeString,
 	})
 
+	smbiosSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMSMBIOS)
+
+	testOptionalArguments(t, smbiosSchema, []string{
+		mkResourceVirtualEnvironmentVMSMBIOSFamily,
+		mkResourceVirtualEnvironmentVMSMBIOSManufacturer,
+		mkResourceVirtualEnvironmentVMSMBIOSProduct,
+		mkResourceVirtualEnvironmentVMSMBIOSSerial,
+		mkResourceVirtualEnvironmentVMSMBIOSSKU,
+		mkResourceVirtualEnvironmentVMSMBIOSUUID,
+		mkResourceVirtualEnvironmentVMSMBIOSVersion,
+	})
+
+	testValueTypes(t, smbiosSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMSMBIOSFamily:       schema.TypeString,
+		mkResourceVirtualEnvironmentVMSMBIOSManufacturer: schema.TypeString,
+		mkResourceVirtualEnvironmentVMSMBIOSProduct:      schema.TypeString,
+		mkResourceVirtualEnvironmentVMSMBIOSSerial:       schema.TypeString,
+		mkResourceVirtualEnvironmentVMSMBIOSSKU:          schema.TypeString,
+		mkResourceVirtualEnvironmentVMSMBIOSUUID:         schema.TypeString,
+		mkResourceVirtualEnvironmentVMSMBIOSVersion:      schema.TypeString,
+	})
+
+	tpmStateSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMTPMState)
+
+	testOptionalArguments(t, tpmStateSchema, []string{
+		mkResourceVirtualEnvironmentVMTPMStateDatastoreID,
+		mkResourceVirtualEnvironmentVMTPMStateVersion,
+	})
+
+	testValueTypes(t, tpmStateSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMTPMStateDatastoreID: schema.TypeString,
+		mkResourceVirtualEnvironmentVMTPMStateVersion:     schema.TypeString,
+	})
+
+	usbDeviceSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMUSBDevice)
+
+	testRequiredArguments(t, usbDeviceSchema, []string{
+		mkResourceVirtualEnvironmentVMUSBDeviceHost,
+	})
+
+	testOptionalArguments(t, usbDeviceSchema, []string{
+		mkResourceVirtualEnvironmentVMUSBDeviceUSB3,
+	})
+
+	testValueTypes(t, usbDeviceSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMUSBDeviceHost: schema.TypeString,
+		mkResourceVirtualEnvironmentVMUSBDeviceUSB3: schema.TypeBool,
+	})
+
 	vgaSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMVGA)
 
 	testOptionalArguments(t, vgaSchema, []string{
@@ -340,4 +501,140 @@ func TestResourceVirtualEnvironmentVMSchema(t *testing.T) {
 		mkResourceVirtualEnvironmentVMVGAMemory:  schema.TypeInt,
 		mkResourceVirtualEnvironmentVMVGAType:    schema.TypeString,
 	})
+
+	watchdogDeviceSchema := testNestedSchemaExistence(t, s, mkResourceVirtualEnvironmentVMWatchdogDevice)
+
+	testOptionalArguments(t, watchdogDeviceSchema, []string{
+		mkResourceVirtualEnvironmentVMWatchdogDeviceAction,
+		mkResourceVirtualEnvironmentVMWatchdogDeviceModel,
+	})
+
+	testValueTypes(t, watchdogDeviceSchema, map[string]schema.ValueType{
+		mkResourceVirtualEnvironmentVMWatchdogDeviceAction: schema.TypeString,
+		mkResourceVirtualEnvironmentVMWatchdogDeviceModel:  schema.TypeString,
+	})
+}
+
+// TestResourceVirtualEnvironmentVMImport tests whether an imported VM matches an equivalent configuration without changes.
+func TestResourceVirtualEnvironmentVMImport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api2/json/access/ticket":
+			fmt.Fprint(w, `{"data":{"CSRFPreventionToken":"token","ticket":"ticket","username":"root@pam"}}`)
+		case "/api2/json/nodes/first-node/qemu/100/config":
+			fmt.Fprint(w, `{"data":{`+
+				`"arch":"x86_64",`+
+				`"bootdisk":"scsi0",`+
+				`"cores":2,`+
+				`"ide2":"local-lvm:vm-100-cloudinit,media=cdrom",`+
+				`"ipconfig0":"ip=192.168.0.10/24,gw=192.168.0.1,ip6=fd00::10/64",`+
+				`"keyboard":"en-us",`+
+				`"memory":1024,`+
+				`"name":"imported",`+
+				`"net0":"virtio=AA:BB:CC:DD:EE:FF,bridge=vmbr0",`+
+				`"ostype":"other",`+
+				`"scsi0":"local:100/vm-100-disk-0.raw,format=raw,size=16G",`+
+				`"scsihw":"virtio-scsi-pci",`+
+				`"sockets":1,`+
+				`"vga":"std,memory=16"`+
+				`}}`)
+		case "/api2/json/nodes/first-node/qemu/100/status/current":
+			fmt.Fprint(w, `{"data":{"status":"stopped"}}`)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+			fmt.Fprint(w, `{"data":null}`)
+		}
+	}))
+
+	defer server.Close()
+
+	veClient, err := proxmox.NewVirtualEnvironmentClient(server.URL, "root@pam", "password", "", "", true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := providerConfiguration{
+		stopContext: context.Background(),
+		veClient:    veClient,
+	}
+
+	r := resourceVirtualEnvironmentVM()
+	d := r.TestResourceData()
+	d.SetId("first-node/100")
+
+	imported, err := r.Importer.State(d, m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.Read(imported[0], m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		mkResourceVirtualEnvironmentVMCPU: []interface{}{
+			map[string]interface{}{
+				mkResourceVirtualEnvironmentVMCPUCores: 2,
+			},
+		},
+		mkResourceVirtualEnvironmentVMDisk: []interface{}{
+			map[string]interface{}{
+				mkResourceVirtualEnvironmentVMDiskDatastoreID: "local",
+				mkResourceVirtualEnvironmentVMDiskFileFormat:  "raw",
+				mkResourcevirtualEnvironmentVMDiskInterface:   "scsi0",
+				mkResourceVirtualEnvironmentVMDiskSize:        16,
+			},
+		},
+		mkResourceVirtualEnvironmentVMInitialization: []interface{}{
+			map[string]interface{}{
+				mkResourceVirtualEnvironmentVMInitializationIPConfig: []interface{}{
+					map[string]interface{}{
+						mkResourceVirtualEnvironmentVMInitializationIPConfigIPv4: []interface{}{
+							map[string]interface{}{
+								mkResourceVirtualEnvironmentVMInitializationIPConfigIPv4Address: "192.168.0.10/24",
+								mkResourceVirtualEnvironmentVMInitializationIPConfigIPv4Gateway: "192.168.0.1",
+							},
+						},
+						mkResourceVirtualEnvironmentVMInitializationIPConfigIPv6: []interface{}{
+							map[string]interface{}{
+								mkResourceVirtualEnvironmentVMInitializationIPConfigIPv6Address: "fd00::10/64",
+							},
+						},
+					},
+				},
+			},
+		},
+		mkResourceVirtualEnvironmentVMMemory: []interface{}{
+			map[string]interface{}{
+				mkResourceVirtualEnvironmentVMMemoryDedicated: 1024,
+			},
+		},
+		mkResourceVirtualEnvironmentVMName: "imported",
+		mkResourceVirtualEnvironmentVMNetworkDevice: []interface{}{
+			map[string]interface{}{
+				mkResourceVirtualEnvironmentVMNetworkDeviceMACAddress: "AA:BB:CC:DD:EE:FF",
+			},
+		},
+		mkResourceVirtualEnvironmentVMNodeName: "first-node",
+		mkResourceVirtualEnvironmentVMStarted:  false,
+		mkResourceVirtualEnvironmentVMVMID:     100,
+	})
+
+	diff, err := r.Diff(imported[0].State(), config, m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != nil && !diff.Empty() {
+		for k, v := range diff.Attributes {
+			t.Errorf("Attribute \"%s\" changes from \"%s\" to \"%s\" after import", k, v.Old, v.New)
+		}
+	}
 }
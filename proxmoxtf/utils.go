@@ -465,6 +465,14 @@ func parseDiskSize(size *string) (int, error) {
 			}
 
 			diskSize = int(math.Ceil(float64(diskSize) / 1024))
+		} else if strings.HasSuffix(*size, "K") {
+			diskSize, err = strconv.Atoi(strings.TrimSuffix(*size, "K"))
+
+			if err != nil {
+				return -1, err
+			}
+
+			diskSize = int(math.Ceil(float64(diskSize) / 1024 / 1024))
 		} else {
 			return -1, fmt.Errorf("Cannot parse storage size \"%s\"", *size)
 		}
@@ -472,6 +480,23 @@ func parseDiskSize(size *string) (int, error) {
 	return diskSize, err
 }
 
+// parseNodeResourceID parses an import identifier in the format node_name/id.
+func parseNodeResourceID(id string) (string, int, error) {
+	idParts := strings.Split(id, "/")
+
+	if len(idParts) != 2 || idParts[0] == "" {
+		return "", 0, fmt.Errorf("Invalid import identifier \"%s\" (valid: node_name/id)", id)
+	}
+
+	resourceID, err := strconv.Atoi(idParts[1])
+
+	if err != nil || resourceID < 1 {
+		return "", 0, fmt.Errorf("Invalid import identifier \"%s\" (valid: node_name/id)", id)
+	}
+
+	return idParts[0], resourceID, nil
+}
+
 func getCloudInitTypeValidator() schema.SchemaValidateFunc {
 	return validation.StringInSlice([]string{
 		"configdrive2",